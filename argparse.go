@@ -5,17 +5,28 @@ import (
 	"net"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/cmj0121/logger"
 )
 
 var (
-	Stderr           = os.Stderr
-	ExitWhenCallback = true
-	log              = logger.New(PROJ_NAME)
+	Stderr                   = os.Stderr
+	ExitWhenCallback         = true
+	log              logging = logger.New(PROJ_NAME)
 )
 
+// the logging methods used in the parser
+type logging interface {
+	Info(format string, args ...interface{})
+	Verbose(format string, args ...interface{})
+	Debug(format string, args ...interface{})
+	Warn(format string, args ...interface{})
+}
+
 func MustNew(in interface{}) (parser *ArgParse) {
 	var err error
 
@@ -44,10 +55,30 @@ func New(in interface{}) (parser *ArgParse, err error) {
 	parser = &ArgParse{
 		Value: value,
 		Name:  name,
+		Theme: DefaultTheme,
 
 		used_option:     map[string]*Field{},
 		used_shortcut:   map[rune]*Field{},
 		used_subcommand: map[string]*Field{},
+		exclusive:       map[string]*Field{},
+	}
+
+	// set the extra help message from the structure, the method promoted from the embedded
+	// sub-command is skipped
+	if describer, ok := in.(Describer); ok && isDeclaredMethod(value.Type(), "Description") {
+		parser.Description = describer.Description()
+	}
+	if exampler, ok := in.(Exampler); ok && isDeclaredMethod(value.Type(), "Examples") {
+		parser.Examples = exampler.Examples()
+	}
+	if epiloger, ok := in.(Epiloger); ok && isDeclaredMethod(value.Type(), "Epilog") {
+		parser.Epilog = epiloger.Epilog()
+	}
+	if versioner, ok := in.(Versioner); ok && isDeclaredMethod(value.Type(), "VersionString") {
+		parser.Version = versioner.VersionString()
+	}
+	if templater, ok := in.(HelpTemplater); ok && isDeclaredMethod(value.Type(), "HelpTemplate") {
+		parser.HelpTemplate = templater.HelpTemplate()
 	}
 
 	// process the field
@@ -64,12 +95,21 @@ func New(in interface{}) (parser *ArgParse, err error) {
 			continue
 		}
 
-		if err = parser.setField(v, field); err != nil {
+		if err = parser.setField(v, field, ""); err != nil {
 			err = fmt.Errorf("cannot processed %v.%v: %v", typ.Name(), field.Name, err)
 			return
 		}
 	}
 
+	// the snapshot of the value with the default, restored by Reset
+	for _, fields := range [][]*Field{parser.options, parser.arguments, parser.subcommands} {
+		for _, field := range fields {
+			field.initial = cloneValue(field.Value)
+		}
+	}
+	if parser.remainder != nil {
+		parser.remainder.initial = cloneValue(parser.remainder.Value)
+	}
 	return
 }
 
@@ -79,55 +119,109 @@ type ArgParse struct {
 
 	// the program name for the parser, default is the name of passed structure as lowercase
 	Name string
-	// set exit when callback success
+	// the version of the program, the deprecated field raise error since the removed version
+	Version string
+	// the format of the --version message: VERSION_SHORT (default), VERSION_VERBOSE or VERSION_JSON
+	VersionFormat string
+	// allow the unambiguous prefix of the long option and the sub-command
+	AllowPrefix bool
+
+	// the extra help message: the description after the usage, the examples and the epilog at the end
+	Description string
+	Examples    []Example
+	Epilog      string
+	// the customized template of the help message, use DEFAULT_HELP_TEMPLATE when empty
+	HelpTemplate string
+	// the theme of the help and error message, never styled when nil
+	Theme *Theme
+	// prompt the missing required values when the stdin is a tty
+	Interactive bool
+	// reset the value as the default before each Parse, the parser can be reused
+	AutoReset bool
+	// the prefix of the response file expanded as the arguments, like @args.txt, disabled when empty
+	ResponsePrefix string
+	// the lookup of the variable expanded by ParseString, like os.LookupEnv, never expanded when nil
+	LookupEnv func(string) (string, bool)
+	// the source of the secret serialized by Args and Config by the option name, like "env:API_TOKEN", the
+	// secret is serialized as "-" which needs the tty when parsed back when nil or returns empty
+	SecretSource func(string) string
 
 	// the field in the argparse
 	options     []*Field
 	arguments   []*Field
 	subcommands []*Field
+	remainder   *Field
 
 	// the cache for the used options
 	used_option     map[string]*Field
 	used_shortcut   map[rune]*Field
 	used_subcommand map[string]*Field
+	// the option passed in the mutually exclusive set, reset on each Parse
+	exclusive map[string]*Field
+
+	// the groups of the options and sub-commands, shown in order
+	groups []*Group
+
+	// the full path of the program, set when dispatch from the parent parser
+	path string
+
+	// never exit the process, and stop parsing when the callback returns true, used by the shell
+	no_exit bool
+	stopped bool
+
+	// the pending format of the configuration dump, dumped after parsed
+	dump_format string
+
+	// collect the unrecognized tokens instead of raising error, used by ParseKnown
+	known_only bool
+	unknown    []string
 }
 
-func (parser *ArgParse) setField(val reflect.Value, field reflect.StructField) (err error) {
+func (parser *ArgParse) setField(val reflect.Value, field reflect.StructField, group string) (err error) {
 	var new_field *Field
 
-	log.Debug("try set field: %v (%v) (%v)", val, val.Type(), field.Tag)
+	// the pre-filled secret never logged
+	redacted := (&Field{Secret: isSecretField(field)}).redact(val.Interface())
+	log.Debug("try set field: %v (%v) (%v)", redacted, val.Type(), field.Tag)
 
 	switch field.Tag.Get(TAG_RESERVED_KEY) {
 	case TAG_IGNORE:
-		log.Info("skip field: %v (%v)", val, field.Tag)
+		log.Info("skip field: %v (%v)", field.Name, field.Tag)
 		return
+	case TAG_REMAINDER:
+		log.Debug("remainder: %v", field.Type)
+
+		switch val.Interface().(type) {
+		case []string, *[]string:
+		default:
+			err = fmt.Errorf("remainder should be []string: %v", field.Type)
+			return
+		}
+
+		if parser.remainder != nil {
+			err = fmt.Errorf("duplicated remainder %v", parser.remainder.Name)
+			return
+		}
+
+		if new_field, err = NewField(val, field, REMAINDER); err != nil {
+			return
+		}
+		parser.remainder = new_field
 	default:
 		switch {
 		case field.Type.Kind() == reflect.Ptr: // argument or sub-command
 			log.Debug("argument or sub-command: %v", field.Type.Elem().Kind())
 
-			switch field.Tag.Get(TAG_RESERVED_KEY) {
-			case TAG_OPTION:
+			switch key := field.Tag.Get(TAG_RESERVED_KEY); {
+			case key == TAG_OPTION || key == KEY_PASSWORD || isSecret(field.Type):
 				log.Info("force set as option: %[1]T", val.Interface())
 				if new_field, err = NewField(val, field, OPTION); err != nil {
 					return
 				}
 
-				if _, ok := parser.used_option["--"+new_field.Name]; ok {
-					err = fmt.Errorf("duplicated option --%v", new_field.Name)
+				if err = parser.addOption(new_field); err != nil {
 					return
 				}
-				parser.used_option["--"+new_field.Name] = new_field
-
-				if new_field.Shortcut != rune(0) {
-					if _, ok := parser.used_option["-"+string(new_field.Shortcut)]; ok {
-						err = fmt.Errorf("duplicated option -%v", string(new_field.Shortcut))
-						return
-					}
-					parser.used_option["-"+string(new_field.Shortcut)] = new_field
-				}
-
-				parser.options = append(parser.options, new_field)
 			default:
 				switch field.Type.Elem().Kind() {
 				case reflect.Struct:
@@ -142,12 +236,9 @@ func (parser *ArgParse) setField(val reflect.Value, field reflect.StructField) (
 							return
 						}
 
-						if _, ok := parser.used_subcommand[new_field.Name]; ok {
-							err = fmt.Errorf("duplicated subcommands %v", new_field.Name)
+						if err = parser.addSubcommand(new_field); err != nil {
 							return
 						}
-						parser.used_subcommand[new_field.Name] = new_field
-						parser.subcommands = append(parser.subcommands, new_field)
 					}
 				default:
 					if new_field, err = NewField(val, field, ARGUMENT); err != nil {
@@ -156,9 +247,40 @@ func (parser *ArgParse) setField(val reflect.Value, field reflect.StructField) (
 					parser.arguments = append(parser.arguments, new_field)
 				}
 			}
+		case field.Type.Kind() == reflect.Struct && !field.Anonymous && isGroup(val): // the named nested group
+			log.Debug("group field: %T", val.Interface())
+
+			name := strings.TrimSpace(field.Tag.Get(TAG_GROUP))
+			if name == "" {
+				// use the field name as the group name
+				name = field.Name
+			}
+			parser.SetGroup(name, field.Tag.Get(TAG_HELP))
+
+			for idx := 0; idx < field.Type.NumField(); idx++ {
+				v := val.Field(idx)
+
+				sub_field := field.Type.Field(idx)
+				if !v.CanSet() || strings.TrimSpace(string(sub_field.Tag)) == TAG_IGNORE {
+					// the field will not be processed, skip
+					log.Info("#%-2d field %v.%v skip", idx, field.Name, sub_field.Name)
+					continue
+				}
+
+				if err = parser.setField(v, sub_field, name); err != nil {
+					err = fmt.Errorf("set %v.%v: %v", field.Name, sub_field.Name, err)
+					return
+				}
+			}
 		case field.Type.Kind() == reflect.Struct && field.Anonymous: // embedded field
 			log.Debug("embedded field: %T", val.Interface())
 
+			if name := strings.TrimSpace(field.Tag.Get(TAG_GROUP)); name != "" {
+				// the embedded field with the group
+				parser.SetGroup(name, field.Tag.Get(TAG_HELP))
+				group = name
+			}
+
 			switch val.Interface().(type) {
 			default:
 				for idx := 0; idx < field.Type.NumField(); idx++ {
@@ -171,7 +293,7 @@ func (parser *ArgParse) setField(val reflect.Value, field reflect.StructField) (
 						continue
 					}
 
-					if err = parser.setField(v, sub_field); err != nil {
+					if err = parser.setField(v, sub_field, group); err != nil {
 						err = fmt.Errorf("set %v.%v: %v", field.Name, sub_field.Name, err)
 						return
 					}
@@ -182,24 +304,25 @@ func (parser *ArgParse) setField(val reflect.Value, field reflect.StructField) (
 				return
 			}
 
-			if _, ok := parser.used_option["--"+new_field.Name]; ok {
-				err = fmt.Errorf("duplicated option --%v", new_field.Name)
+			if err = parser.addOption(new_field); err != nil {
 				return
 			}
-			parser.used_option["--"+new_field.Name] = new_field
-
-			if new_field.Shortcut != rune(0) {
-				if _, ok := parser.used_option["-"+string(new_field.Shortcut)]; ok {
-					err = fmt.Errorf("duplicated option -%v", string(new_field.Shortcut))
-					return
-				}
-				parser.used_option["-"+string(new_field.Shortcut)] = new_field
-			}
+		}
+	}
 
-			parser.options = append(parser.options, new_field)
+	if new_field != nil && new_field.Group == "" && group != "" {
+		switch new_field.FieldType {
+		case OPTION, SUBCOMMAND:
+			// inherit the group from the nested structure
+			new_field.Group = group
 		}
 	}
 
+	if new_field != nil && new_field.Group != "" {
+		// register the group by the order of appearance
+		parser.addGroup(new_field.Group)
+	}
+
 	if new_field != nil && new_field.Callback != "" && GetCallback(parser.Value, new_field.Callback) == nil {
 		err = fmt.Errorf("callback %v not defined", new_field.Callback)
 		return
@@ -209,6 +332,165 @@ func (parser *ArgParse) setField(val reflect.Value, field reflect.StructField) (
 	return
 }
 
+// the group of the options and sub-commands shown in the help message
+type Group struct {
+	Name        string
+	Description string
+}
+
+// set the description of the group, and register it when not exists
+func (parser *ArgParse) SetGroup(name, description string) {
+	group := parser.addGroup(name)
+	if description = strings.TrimSpace(description); description != "" {
+		// override the description
+		group.Description = description
+	}
+}
+
+// set the order of the groups shown in the help message, the groups not listed are shown
+// after the listed groups in the order of appearance
+func (parser *ArgParse) SetGroupOrder(names ...string) {
+	groups := []*Group{}
+	for _, name := range names {
+		groups = append(groups, parser.addGroup(name))
+	}
+
+	for _, group := range parser.groups {
+		found := false
+		for _, g := range groups {
+			found = found || g == group
+		}
+
+		if !found {
+			groups = append(groups, group)
+		}
+	}
+	parser.groups = groups
+}
+
+func (parser *ArgParse) addGroup(name string) (group *Group) {
+	for _, group = range parser.groups {
+		if group.Name == name {
+			// already exists
+			return
+		}
+	}
+
+	group = &Group{Name: name}
+	parser.groups = append(parser.groups, group)
+	return
+}
+
+// the nested structure is treated as the group, except the built-in types
+func isGroup(val reflect.Value) (ok bool) {
+	switch val.Interface().(type) {
+	case time.Time, net.IPNet, net.Interface, os.File:
+	default:
+		ok = true
+	}
+	return
+}
+
+// register the option into the parser, and check the duplicated name
+func (parser *ArgParse) addOption(field *Field) (err error) {
+	names, shortcuts := []string{field.Name}, []rune{}
+	if field.Shortcut != rune(0) {
+		// the shortcut of the option
+		shortcuts = append(shortcuts, field.Shortcut)
+	}
+
+	aliases := append(field.Aliases[:len(field.Aliases):len(field.Aliases)], field.HiddenAliases...)
+	for _, alias := range aliases {
+		switch runes := []rune(alias); len(runes) {
+		case 1:
+			shortcuts = append(shortcuts, runes[0])
+		default:
+			names = append(names, alias)
+		}
+	}
+
+	if field.Negatable() {
+		// the negative option, include the visible alias like --no-colour
+		for _, name := range names {
+			if !field.isHiddenAlias(name) {
+				names = append(names, "no-"+name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := parser.used_option["--"+name]; ok {
+			err = fmt.Errorf("duplicated option --%v", name)
+			return
+		}
+		parser.used_option["--"+name] = field
+	}
+
+	for _, shortcut := range shortcuts {
+		if _, ok := parser.used_shortcut[shortcut]; ok {
+			err = fmt.Errorf("duplicated option -%v", string(shortcut))
+			return
+		}
+		parser.used_shortcut[shortcut] = field
+	}
+
+	parser.options = append(parser.options, field)
+	return
+}
+
+// register the sub-command into the parser, and check the duplicated name
+func (parser *ArgParse) addSubcommand(field *Field) (err error) {
+	names := append([]string{field.Name}, field.Aliases...)
+	names = append(names, field.HiddenAliases...)
+
+	for _, name := range names {
+		if _, ok := parser.used_subcommand[name]; ok {
+			err = fmt.Errorf("duplicated subcommands %v", name)
+			return
+		}
+		parser.used_subcommand[name] = field
+	}
+
+	parser.subcommands = append(parser.subcommands, field)
+	return
+}
+
+// find the unique key by the prefix when enable AllowPrefix, raise error when ambiguous
+func (parser *ArgParse) matchPrefix(prefix string, used map[string]*Field) (key string, err error) {
+	matched := map[*Field]string{}
+	for name, field := range used {
+		if !strings.HasPrefix(name, prefix) {
+			// not matched
+			continue
+		} else if field.Hidden || field.isHiddenAlias(strings.TrimPrefix(name, "--")) {
+			// the hidden name is only matched exactly
+			continue
+		}
+
+		if k, ok := matched[field]; !ok || len(name) < len(k) || (len(name) == len(k) && name < k) {
+			// always use the shortest name for the same field
+			matched[field] = name
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+	case 1:
+		for _, key = range matched {
+			log.Info("match %v by prefix %v", key, prefix)
+		}
+	default:
+		candidates := []string{}
+		for _, name := range matched {
+			candidates = append(candidates, name)
+		}
+		sort.Strings(candidates)
+
+		err = fmt.Errorf("ambiguous %v: did you mean %v?", prefix, joinOr(candidates))
+	}
+	return
+}
+
 func (parser *ArgParse) Run() (err error) {
 	if err = parser.Parse(os.Args[1:]...); err != nil {
 		// show the help message
@@ -218,19 +500,116 @@ func (parser *ArgParse) Run() (err error) {
 }
 
 func (parser *ArgParse) Parse(args ...string) (err error) {
-	log.Info("parse %#v", args)
+	if parser.ResponsePrefix != "" {
+		// expand the response files
+		if args, err = parser.expandResponse(args, "", 0); err != nil {
+			return
+		}
+	}
 
-	for idx, size := 0, 0; idx < len(args); idx += size {
+	// the secret value never shown in the log
+	redacted := parser.redactArgs(args)
+	log.Info("parse %#v", redacted)
+
+	if parser.AutoReset {
+		// start from the default
+		parser.Reset()
+	}
+	parser.exclusive = map[string]*Field{}
+
+	defer func() {
+		if err != nil || parser.dump_format == "" || parser.stopped {
+			return
+		}
+
+		// dump the configuration with all the arguments parsed
+		format := parser.dump_format
+		parser.dump_format = ""
+
+		var str string
+		if str, err = parser.DumpConfig(format); err != nil {
+			return
+		}
+		os.Stdout.WriteString(str)
+
+		switch {
+		case parser.no_exit:
+			log.Info("dump the configuration as %v, and stop parsing", format)
+			parser.stopped = true
+		case ExitWhenCallback:
+			log.Info("dump the configuration as %v, and exit 0", format)
+			os.Exit(0)
+		}
+	}()
+
+	// all the tokens after -- are treated as the argument
+	no_option := false
+	for idx, size := 0, 0; idx < len(args) && !parser.stopped; idx += size {
 		token := args[idx]
 
-		log.Info("%v parse #%-2d %v", parser.Name, idx, token)
+		log.Info("%v parse #%-2d %v", parser.Name, idx, redacted[idx])
 	PROCESS_FIELD:
 		switch {
-		case len(token) > 2 && token[:2] == "--":
-			log.Debug("optional: %v", token)
+		case token == "--" && !no_option:
+			if parser.remainder != nil {
+				log.Info("set remainder %v", parser.remainder.Name)
+				if err = parser.remainder.SetRemainder(parser, args[idx+1:]...); err != nil {
+					// cannot set the value, raise
+					err = fmt.Errorf("%v %v", parser.remainder.Name, err)
+					return
+				}
+				err = parser.promptInteractive()
+				return
+			}
+
+			log.Debug("end of the option")
+			no_option = true
+			size = 1
+		case len(token) > 2 && token[:2] == "--" && !no_option:
+			log.Debug("optional: %v", redacted[idx])
+
+			// the option may pass the explicit value, like --NAME=VALUE
+			name, value, explicit := token, "", false
+			if pos := strings.Index(token, "="); pos > 0 {
+				name, value, explicit = token[:pos], token[pos+1:], true
+			}
+
+			if _, ok := parser.used_option[name]; !ok && parser.AllowPrefix {
+				var key string
+				if key, err = parser.matchPrefix(name, parser.used_option); err != nil {
+					// ambiguous option
+					return
+				} else if key != "" {
+					name = key
+				}
+			}
+
+			if field, ok := parser.used_option[name]; ok {
+				switch {
+				case field.isNegative(name):
+					if explicit {
+						err = fmt.Errorf("%v should not pass value: %v", name, value)
+						return
+					}
 
-			for _, field := range parser.options {
-				if field.Name == token[2:] {
+					// set the negative value
+					if err = field.SetNegative(parser); err != nil {
+						// cannot set the value, raise
+						err = fmt.Errorf("%v %v", name, err)
+						return
+					}
+
+					size = 1
+				case explicit:
+					// set the explicit value
+					if err = field.SetExplicitValue(parser, value); err != nil {
+						// cannot set the value, raise
+						err = fmt.Errorf("%v %v", name, err)
+						return
+					}
+
+					size = 1
+				default:
 					// set the value
 					if size, err = field.SetValue(parser, args[idx+1:]...); err != nil {
 						// cannot set the value, raise
@@ -239,76 +618,110 @@ func (parser *ArgParse) Parse(args ...string) (err error) {
 					}
 
 					size++
-					break PROCESS_FIELD
 				}
+				break PROCESS_FIELD
+			}
+
+			if parser.known_only {
+				log.Info("unknown option: %v, skip", token)
+				parser.unknown = append(parser.unknown, token)
+				size = 1
+				break PROCESS_FIELD
 			}
 
 			log.Warn("unknown option: %v", token)
-			err = fmt.Errorf("unknown option: %v", token)
+			err = fmt.Errorf("unknown option: %v%v", token, parser.suggestOption(name))
 			return
-		case len(token) > 1 && token[:1] == "-":
+		case len(token) > 1 && token[:1] == "-" && !no_option:
 			log.Debug("shortcut: %v (%d)", token[1:], WidecharSize(token[1:]))
 
 			switch {
-			case WidecharSize(token[1:]) == 1 || (WidecharSize(token[1:]) == 2 && len(token[1:]) > 2):
+			case utf8.RuneCountInString(token[1:]) == 1:
 				shortcut := []rune(token[1:])[0]
 
-				for _, field := range parser.options {
-					if field.Shortcut == shortcut {
-						if size, err = field.SetValue(parser, args[idx+1:]...); err != nil {
-							// cannot set the value, raise
-							err = fmt.Errorf("%v %v", token, err)
-							return
-						}
-
-						size++
-						break PROCESS_FIELD
+				if field, ok := parser.used_shortcut[shortcut]; ok {
+					if size, err = field.SetValue(parser, args[idx+1:]...); err != nil {
+						// cannot set the value, raise
+						err = fmt.Errorf("%v %v", token, err)
+						return
 					}
+
+					size++
+					break PROCESS_FIELD
 				}
 			default:
 				for _, shortcut := range token[1:] {
-					found := false
-					for _, field := range parser.options {
-						if field.Shortcut == shortcut {
-							if _, err = field.SetValue(parser); err != nil {
-								// cannot set the value, raise
-								err = fmt.Errorf("multi-shortcut %#v cannot set: %v", token, err)
-								return
-							}
-
-							found = true
+					field, found := parser.used_shortcut[shortcut]
+					if found {
+						if _, err = field.SetValue(parser); err != nil {
+							// cannot set the value, raise
+							err = fmt.Errorf("multi-shortcut %#v cannot set: %v", token, err)
+							return
 						}
 					}
 
 					if !found {
-						err = fmt.Errorf("unknown option: -%v", string(shortcut))
+						if parser.known_only {
+							log.Info("unknown option: -%v, skip", string(shortcut))
+							parser.unknown = append(parser.unknown, "-"+string(shortcut))
+							continue
+						}
+
+						msg := parser.suggestOption("-" + string(shortcut))
+						if msg == "" {
+							// may be the long option with single dash
+							msg = parser.suggestOption(token)
+						}
+						err = fmt.Errorf("unknown option: -%v%v", string(shortcut), msg)
 						return
 					}
 				}
 
 				// skip this option
-				size++
+				size = 1
 				break PROCESS_FIELD
 			}
 
-			err = fmt.Errorf("unknown option: %v", token)
+			if parser.known_only {
+				log.Info("unknown option: %v, skip", token)
+				parser.unknown = append(parser.unknown, token)
+				size = 1
+				break PROCESS_FIELD
+			}
+
+			err = fmt.Errorf("unknown option: %v%v", token, parser.suggestOption(token))
 			return
 		default:
 			log.Debug("argument or sub-command: %v", token)
 
 			// check the sub-command first
-			for _, field := range parser.subcommands {
-				if field.Name == token {
-					log.Info("set sub-command %v", field.Name)
-					if _, err = field.SetValue(parser, args[idx+1:]...); err != nil {
-						// cannot set the value, raise
-						err = fmt.Errorf("%v %v", field.Name, err)
-						return
-					}
+			name := token
+			if _, ok := parser.used_subcommand[name]; !ok && parser.AllowPrefix && !no_option {
+				var key string
+				if key, err = parser.matchPrefix(name, parser.used_subcommand); err != nil {
+					// ambiguous sub-command
+					return
+				} else if key != "" {
+					name = key
+				}
+			}
 
-					// always return when process sub-command
+			if field, ok := parser.used_subcommand[name]; ok && !no_option {
+				log.Info("set sub-command %v", field.Name)
+
+				parser.inherit(field.Subcommand)
+				if _, err = field.SetValue(parser, args[idx+1:]...); err != nil {
+					// cannot set the value, raise
+					err = fmt.Errorf("%v %v", field.Name, err)
 					return
 				}
+
+				parser.unknown = append(parser.unknown, field.Subcommand.unknown...)
+				parser.stopped = parser.stopped || field.Subcommand.stopped
+				field.Subcommand.known_only = false
+				// always return when process sub-command
+				err = parser.promptInteractive()
+				return
 			}
 
 			for _, field := range parser.arguments {
@@ -326,100 +739,123 @@ func (parser *ArgParse) Parse(args ...string) (err error) {
 				break PROCESS_FIELD
 			}
 
-			log.Warn("unknown argument: %v", token)
-			err = fmt.Errorf("unknown argument: %v", token)
-			return
-		}
-	}
-	return
-}
-
-func (parser *ArgParse) HelpMessage(err error) {
-	msgs := []string{}
-
-	if err != nil {
-		msg := fmt.Sprintf("error: %v", err)
-		msgs = append(msgs, msg)
-	}
-
-	msgs = append(msgs, parser.usage())
-
-	if len(parser.options) > 0 {
-		margin, pending, siz := FMT_MARGIN, FMT_PENDING, FMT_SIZE
-		msgs = append(msgs, []string{"", "option:"}...)
-
-		for _, field := range parser.options {
-			if field.Shortcut != rune(0) {
-				if p := WidecharSize(string(field.Shortcut)) + WidecharSize(field.TypeHint) + 4; p > pending {
-					// override the pending
-					pending = p
+			if parser.remainder != nil {
+				log.Info("set remainder %v from %v", parser.remainder.Name, token)
+				if err = parser.remainder.SetRemainder(parser, args[idx:]...); err != nil {
+					// cannot set the value, raise
+					err = fmt.Errorf("%v %v", parser.remainder.Name, err)
+					return
 				}
+				err = parser.promptInteractive()
+				return
 			}
 
-			if s := WidecharSize(field.Name) + WidecharSize(field.TypeHint) + 6; s > siz {
-				// override the size
-				siz = s
+			if parser.known_only {
+				log.Info("unknown argument: %v, skip", token)
+				parser.unknown = append(parser.unknown, token)
+				size = 1
+				break PROCESS_FIELD
 			}
-		}
 
-		for _, field := range parser.options {
-			log.Debug("format string m:%d, p:%d, s:%d", margin, pending, siz)
-			msgs = append(msgs, field.FormatString(margin, pending, siz))
+			log.Warn("unknown argument: %v", token)
+			err = fmt.Errorf("unknown argument: %v%v", token, parser.suggestSubcommand(token))
+			return
 		}
 	}
 
-	if len(parser.arguments) > 0 {
-		margin, pending, siz := FMT_MARGIN, FMT_PENDING, FMT_SIZE
-		msgs = append(msgs, []string{"", "argument:"}...)
-
-		for _, field := range parser.arguments {
-			if s := WidecharSize(field.Name) + WidecharSize(field.TypeHint) + 4; s > siz {
-				// override the size
-				siz = s
-			}
-		}
+	err = parser.promptInteractive()
+	return
+}
 
-		for _, field := range parser.arguments {
-			log.Debug("format string m:%d, p:%d, s:%d", margin, pending, siz)
-			msgs = append(msgs, field.FormatString(margin, pending, siz))
+// the suggestion of the unknown option, from the long options and the shortcuts
+func (parser *ArgParse) suggestOption(token string) (msg string) {
+	candidates := []string{}
+	for name, field := range parser.used_option {
+		if field.Hidden || field.isHiddenAlias(name[2:]) {
+			// never suggest the hidden option
+			continue
 		}
+		candidates = append(candidates, name)
 	}
 
-	if len(parser.subcommands) > 0 {
-		margin, pending, siz := FMT_MARGIN, FMT_PENDING, FMT_SIZE
-		msgs = append(msgs, []string{"", "sub-command:"}...)
-
-		for _, field := range parser.subcommands {
-			if s := WidecharSize(field.Name) + WidecharSize(field.TypeHint) + 4; s > siz {
-				// override the size
-				siz = s
+	switch {
+	case len(token) > 2 && token[:2] != "--":
+		// the long option with single dash, like -verbose
+		msg = suggestMessage("-"+token, candidates)
+	case len([]rune(token)) == 2 && SuggestThreshold > 0:
+		// the shortcut only suggested when differ in the case, like -V and -v
+		shortcuts := []string{}
+		for shortcut, field := range parser.used_shortcut {
+			if !field.Hidden && !field.isHiddenAlias(string(shortcut)) && strings.EqualFold(token[1:], string(shortcut)) {
+				shortcuts = append(shortcuts, "-"+string(shortcut))
 			}
 		}
+		if len(shortcuts) > 0 {
+			sort.Strings(shortcuts)
+			msg = fmt.Sprintf(", did you mean %v?", joinOr(shortcuts))
+		}
+	default:
+		msg = suggestMessage(token, candidates)
+	}
+	return
+}
 
-		for _, field := range parser.subcommands {
-			log.Debug("format string m:%d, p:%d, s:%d", margin, pending, siz)
-			msgs = append(msgs, field.FormatString(margin, pending, siz))
+// the suggestion of the unknown argument, from the sub-commands
+func (parser *ArgParse) suggestSubcommand(token string) (msg string) {
+	candidates := []string{}
+	for name, field := range parser.used_subcommand {
+		if field.Hidden || field.isHiddenAlias(name) {
+			// never suggest the hidden sub-command
+			continue
 		}
+		candidates = append(candidates, name)
 	}
 
-	msg := strings.Join(msgs, "\n") + "\n"
-	Stderr.WriteString(msg)
+	msg = suggestMessage(token, candidates)
+	return
 }
 
-func (parser *ArgParse) usage() (str string) {
-	str = fmt.Sprintf("usage: %v", parser.Name)
+// inherit the setting from the parent parser before process the sub-command
+func (parser *ArgParse) inherit(sub *ArgParse) {
+	sub.known_only, sub.unknown = parser.known_only, nil
+	sub.no_exit, sub.stopped = parser.no_exit, false
+	sub.path = fmt.Sprintf("%v %v", parser.program(), sub.Name)
 
-	if len(parser.options) > 0 {
-		// add the option
-		str = fmt.Sprintf("%v [OPTION]", str)
+	if sub.Version == "" {
+		// use the version of the parent
+		sub.Version = parser.Version
 	}
+	if sub.VersionFormat == "" {
+		// use the version format of the parent
+		sub.VersionFormat = parser.VersionFormat
+	}
+	sub.AllowPrefix = sub.AllowPrefix || parser.AllowPrefix
+	sub.Interactive = sub.Interactive || parser.Interactive
 
-	// add the command
-	for _, field := range parser.arguments {
-		if field.FieldType == ARGUMENT {
-			str = fmt.Sprintf("%v %v", str, strings.ToUpper(field.Name))
-		}
+	if sub.HelpTemplate == "" {
+		// use the help template of the parent
+		sub.HelpTemplate = parser.HelpTemplate
 	}
+	if sub.Theme == nil {
+		// use the theme of the parent
+		sub.Theme = parser.Theme
+	}
+	if sub.SecretSource == nil {
+		// use the secret source of the parent
+		sub.SecretSource = parser.SecretSource
+	}
+}
 
+// parse the arguments and return the unrecognized options and arguments instead of
+// raising error, which can be forwarded to the child process
+func (parser *ArgParse) ParseKnown(args ...string) (unknown []string, err error) {
+	parser.known_only, parser.unknown = true, nil
+	defer func() {
+		// always reset the mode
+		parser.known_only = false
+	}()
+
+	err = parser.Parse(args...)
+	unknown = parser.unknown
 	return
 }
@@ -0,0 +1,365 @@
+package argparse
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	// the type of *os.File, which is treated as the value instead of the pointer
+	fileType = reflect.TypeOf((*os.File)(nil))
+	// the type of net.IP, which is treated as the value instead of the slice
+	ipType = reflect.TypeOf(net.IP{})
+)
+
+// the minimal canonical arguments from the current value, which can be parsed back to the same
+// value: the options not the default as --NAME=VALUE, the arguments in order, the selected (the
+// non-nil) sub-command and the remainder after --. The secret is never serialized but passed as
+// "-", which is prompted on the tty when parsed back and fails in scripts and CI, set SecretSource to
+// pass the secret from the environment variable or the file instead
+func (parser *ArgParse) Args() (args []string, err error) {
+	for _, field := range parser.options {
+		var tokens []string
+		if tokens, err = field.optionArgs(parser); err != nil {
+			err = fmt.Errorf("--%v %v", field.Name, err)
+			return
+		}
+		args = append(args, tokens...)
+	}
+
+	var tokens []string
+	var selected *Field
+	if tokens, selected, err = parser.positionalArgs(); err != nil {
+		return
+	}
+	args = append(args, tokens...)
+
+	if selected != nil {
+		sub, restore := selected.selectedSubcommand(parser)
+		defer restore()
+
+		if tokens, err = sub.Args(); err != nil {
+			err = fmt.Errorf("%v %v", selected.Name, err)
+			return
+		}
+
+		args = append(args, selected.Name)
+		args = append(args, tokens...)
+	}
+
+	log.Info("serialize %v as %#v", parser.Name, parser.redactArgs(args))
+	return
+}
+
+// the tokens of the arguments and the remainder after the options, and the selected sub-command
+// which is serialized after the tokens
+func (parser *ArgParse) positionalArgs() (args []string, selected *Field, err error) {
+	for _, field := range parser.subcommands {
+		if !field.Value.IsNil() && !field.builtin() {
+			selected = field
+			break
+		}
+	}
+
+	var positional, remainder []string
+	if positional, err = parser.argumentArgs(); err != nil {
+		return
+	}
+	if parser.remainder != nil {
+		if remainder, err = parser.remainder.sliceArgs(parser.remainder.Value, parser.remainder.initial); err != nil {
+			err = fmt.Errorf("%v %v", parser.remainder.Name, err)
+			return
+		}
+	}
+
+	for _, token := range positional {
+		if !parser.captured(token) {
+			continue
+		}
+
+		// the argument is treated as the option or the sub-command without --
+		if selected != nil || parser.remainder != nil {
+			err = fmt.Errorf("cannot serialize the argument %#v before the sub-command or remainder", token)
+			return
+		}
+		args = append(args, "--")
+		break
+	}
+	args = append(args, positional...)
+
+	switch {
+	case len(remainder) > 0 && selected != nil:
+		err = fmt.Errorf("cannot serialize both the sub-command %v and the remainder", selected.Name)
+	case len(remainder) > 0:
+		args = append(args, "--")
+		args = append(args, remainder...)
+	}
+	return
+}
+
+// the parser of the selected sub-command with the current value, the value assigned by the caller
+// is copied into the parser of the sub-command until restored
+func (field *Field) selectedSubcommand(parser *ArgParse) (sub *ArgParse, restore func()) {
+	sub, restore = field.Subcommand, func() {}
+	if sub.SecretSource == nil {
+		// use the secret source of the parent
+		sub.SecretSource = parser.SecretSource
+	}
+	if field.Value.Pointer() != sub.Value.Pointer() {
+		saved := cloneValue(sub.Value.Elem())
+		sub.Value.Elem().Set(field.Value.Elem())
+		restore = func() { sub.Value.Elem().Set(saved) }
+	}
+	return
+}
+
+// the field with the built-in callback, like --help, which is the action and never serialized
+func (field *Field) builtin() (ok bool) {
+	switch field.Callback {
+	case FN_HELP, FN_HELP_ALL, FN_HELP_COMMAND, FN_VERSION, FN_VERSION_FORMAT, FN_DUMP_CONFIG:
+		ok = true
+	}
+	return
+}
+
+// the token cannot be passed as the argument without --, like the option or the sub-command
+func (parser *ArgParse) captured(token string) (ok bool) {
+	if len(token) > 1 && token[0] == '-' {
+		// the option
+		ok = true
+		return
+	}
+
+	if _, ok = parser.used_subcommand[token]; !ok && parser.AllowPrefix {
+		key, err := parser.matchPrefix(token, parser.used_subcommand)
+		ok = key != "" || err != nil
+	}
+	return
+}
+
+// the tokens of the option which is not the default
+func (field *Field) optionArgs(parser *ArgParse) (args []string, err error) {
+	if field.builtin() {
+		return
+	}
+
+	value, initial := field.Value, field.initial
+	if value.Kind() == reflect.Ptr && value.Type() != fileType {
+		if value.IsNil() {
+			// never set
+			return
+		}
+
+		if value = value.Elem(); !initial.IsNil() {
+			initial = initial.Elem()
+		} else {
+			// the nil pointer is allocated when set
+			initial = reflect.Value{}
+		}
+	}
+
+	switch {
+	case field.IsBool():
+		from := initial.IsValid() && initial.Bool()
+		switch on := value.Bool(); {
+		case initial.IsValid() && from == on:
+		case on, field.Action != ACTION_SET && initial.IsValid():
+			// set as true, or toggle the default
+			args = append(args, "--"+field.Name)
+		case field.Negatable():
+			args = append(args, "--no-"+field.Name)
+		default:
+			args = append(args, fmt.Sprintf("--%v=%v", field.Name, on))
+		}
+	case field.Action == ACTION_COUNT:
+		var count int64
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if count = value.Int(); initial.IsValid() {
+				count -= initial.Int()
+			}
+		default:
+			if count = int64(value.Uint()); initial.IsValid() {
+				count -= int64(initial.Uint())
+			}
+		}
+
+		if count < 0 {
+			err = fmt.Errorf("cannot decrease the counter: %v", value)
+			return
+		}
+
+		for ; count > 0; count-- {
+			args = append(args, "--"+field.Name)
+		}
+	case value.Kind() == reflect.Slice && value.Type() != ipType:
+		var tokens []string
+		if tokens, err = field.sliceArgs(value, initial); err != nil {
+			return
+		}
+
+		for _, token := range tokens {
+			args = append(args, fmt.Sprintf("--%v=%v", field.Name, token))
+		}
+	default:
+		var token, from string
+		if token, err = formatValue(value); err != nil {
+			return
+		} else if initial.IsValid() {
+			if from, err = formatValue(initial); err != nil || from == token {
+				return
+			}
+		}
+
+		if field.Secret {
+			// never pass the secret on the command line, prompt or read from the source instead
+			token = parser.secretSource(field.Name)
+		}
+		args = append(args, fmt.Sprintf("--%v=%v", field.Name, token))
+	}
+	return
+}
+
+// the source of the serialized secret, "-" which is prompted on the tty by default
+func (parser *ArgParse) secretSource(name string) (source string) {
+	if source = "-"; parser.SecretSource != nil {
+		if src := parser.SecretSource(name); src != "" {
+			source = src
+		}
+	}
+	return
+}
+
+// the positional tokens of the arguments in order, until the last one which is not the default
+func (parser *ArgParse) argumentArgs() (args []string, err error) {
+	pending, missing := []string{}, ""
+	for _, field := range parser.arguments {
+		switch {
+		case field.missing():
+			if missing == "" {
+				missing = field.Name
+			}
+			continue
+		case missing != "":
+			err = fmt.Errorf("%v is set without %v", field.Name, missing)
+			return
+		}
+
+		value, initial := field.Value, field.initial
+		if value.Type() != fileType {
+			if value = value.Elem(); !initial.IsNil() {
+				initial = initial.Elem()
+			} else {
+				initial = reflect.Value{}
+			}
+		}
+
+		changed := !initial.IsValid()
+		switch {
+		case value.Kind() == reflect.Slice && value.Type() != ipType:
+			var tokens []string
+			if tokens, err = field.sliceArgs(value, initial); err != nil {
+				err = fmt.Errorf("%v %v", field.Name, err)
+				return
+			}
+
+			changed = len(tokens) > 0
+			pending = append(pending, tokens...)
+		default:
+			var token, from string
+			if token, err = formatValue(value); err != nil {
+				err = fmt.Errorf("%v %v", field.Name, err)
+				return
+			} else if initial.IsValid() {
+				if from, err = formatValue(initial); err != nil {
+					err = fmt.Errorf("%v %v", field.Name, err)
+					return
+				}
+				changed = from != token
+			}
+
+			// the default argument is still passed when the later one is set
+			pending = append(pending, token)
+		}
+
+		if changed {
+			args, pending = append(args, pending...), []string{}
+		}
+	}
+	return
+}
+
+// the tokens of the elements appended after the default, the slice can only be appended
+func (field *Field) sliceArgs(value, initial reflect.Value) (args []string, err error) {
+	from := 0
+	if initial.IsValid() && !initial.IsNil() {
+		if from = initial.Len(); from > value.Len() {
+			err = fmt.Errorf("should extend the default: %v", initial)
+			return
+		}
+	}
+
+	for idx := 0; idx < value.Len(); idx++ {
+		var token string
+		if token, err = formatValue(value.Index(idx)); err != nil {
+			return
+		}
+
+		if idx < from {
+			var prefix string
+			if prefix, err = formatValue(initial.Index(idx)); err != nil {
+				return
+			} else if prefix != token {
+				err = fmt.Errorf("should extend the default: %v", initial)
+				return
+			}
+			continue
+		}
+
+		args = append(args, token)
+	}
+	return
+}
+
+// format the value as the token, the reverse of the setValue
+func formatValue(value reflect.Value) (token string, err error) {
+	switch v := value.Interface().(type) {
+	case *os.File:
+		if v != nil {
+			token = v.Name()
+		}
+	case os.FileMode:
+		token = strconv.FormatUint(uint64(v), 10)
+	case time.Time:
+		token = v.Format(time.RFC3339Nano)
+	case net.Interface:
+		token = v.Name
+	case net.IP:
+		if v != nil {
+			token = v.String()
+		}
+	case net.IPNet:
+		if v.IP != nil {
+			token = v.String()
+		}
+	default:
+		switch value.Kind() {
+		case reflect.Bool:
+			token = strconv.FormatBool(value.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			token = strconv.FormatInt(value.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			token = strconv.FormatUint(value.Uint(), 10)
+		case reflect.String:
+			// the raw string, include the Secret
+			token = value.String()
+		default:
+			err = fmt.Errorf("not implemented serialize: %v", value.Type())
+		}
+	}
+	return
+}
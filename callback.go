@@ -2,6 +2,7 @@ package argparse
 
 import (
 	"reflect"
+	"runtime"
 )
 
 var (
@@ -35,3 +36,49 @@ func GetCallback(value reflect.Value, name string) (fn Callback) {
 	fn, _ = callbacks[name]
 	return
 }
+
+// the method is declared on the structure itself, not promoted from the embedded field. The method
+// never provided by the anonymous fields is declared, otherwise the structure shadows it only when
+// the method is not the wrapper generated by the compiler for the promotion
+func isDeclaredMethod(typ reflect.Type, name string) (ok bool) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if ok = !isPromotedMethod(typ, name); ok {
+		return
+	}
+
+	for _, t := range []reflect.Type{typ, reflect.PtrTo(typ)} {
+		if method, found := t.MethodByName(name); found {
+			pc := method.Func.Pointer()
+			// the promoted method is the compiler-generated wrapper without the source
+			if file, _ := runtime.FuncForPC(pc).FileLine(pc); file != "<autogenerated>" {
+				ok = true
+				return
+			}
+		}
+	}
+
+	log.Debug("the method %v is promoted into %v", name, typ)
+	return
+}
+
+// the method is in the method set of any anonymous field of the structure
+func isPromotedMethod(typ reflect.Type, name string) (ok bool) {
+	for idx := 0; idx < typ.NumField(); idx++ {
+		field := typ.Field(idx)
+		if !field.Anonymous {
+			continue
+		}
+
+		elem := field.Type
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if _, ok = reflect.PtrTo(elem).MethodByName(name); ok {
+			return
+		}
+	}
+	return
+}
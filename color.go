@@ -0,0 +1,105 @@
+package argparse
+
+import (
+	"os"
+	"strings"
+)
+
+// the ANSI escape code to reset the style
+const ANSI_RESET = "\x1b[0m"
+
+// the theme of the help and error message, each field is the ANSI SGR parameter like "1;33",
+// and the empty one means never styled
+type Theme struct {
+	Header  string
+	Option  string
+	Hint    string
+	Default string
+	Error   string
+	Warning string
+}
+
+var (
+	// the default theme used by the new parser, set nil to disable the style
+	DefaultTheme = &Theme{
+		Header:  "1",
+		Option:  "36",
+		Hint:    "33",
+		Default: "2",
+		Error:   "1;31",
+		Warning: "1;33",
+	}
+)
+
+// style the text by the SGR parameter, return the plain text when the theme is nil
+func (theme *Theme) Style(code, text string) (str string) {
+	if str = text; theme == nil || code == "" || text == "" {
+		// never styled
+		return
+	}
+
+	str = "\x1b[" + code + "m" + text + ANSI_RESET
+	return
+}
+
+// style the text by the name of the theme field, used in the help template
+func (theme *Theme) styleBy(name, text string) (str string) {
+	if theme == nil {
+		// never styled
+		str = text
+		return
+	}
+
+	switch strings.ToLower(name) {
+	case "header":
+		str = theme.Style(theme.Header, text)
+	case "option":
+		str = theme.Style(theme.Option, text)
+	case "hint":
+		str = theme.Style(theme.Hint, text)
+	case "default":
+		str = theme.Style(theme.Default, text)
+	case "error":
+		str = theme.Style(theme.Error, text)
+	case "warning":
+		str = theme.Style(theme.Warning, text)
+	default:
+		str = text
+	}
+	return
+}
+
+func (theme *Theme) option() (code string) {
+	if theme != nil {
+		code = theme.Option
+	}
+	return
+}
+
+func (theme *Theme) hint() (code string) {
+	if theme != nil {
+		code = theme.Hint
+	}
+	return
+}
+
+func (theme *Theme) warning() (code string) {
+	if theme != nil {
+		code = theme.Warning
+	}
+	return
+}
+
+// the theme used when write to the file: disabled when NO_COLOR is set or the file is not a
+// tty, and CLICOLOR_FORCE forces to enable the style
+func themeFor(theme *Theme, f *os.File) (enabled *Theme) {
+	switch {
+	case theme == nil:
+	case os.Getenv("NO_COLOR") != "":
+	case os.Getenv("CLICOLOR_FORCE") != "" && os.Getenv("CLICOLOR_FORCE") != "0":
+		enabled = theme
+	case f != nil && isTerminal(f.Fd()):
+		enabled = theme
+	}
+	return
+}
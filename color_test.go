@@ -0,0 +1,34 @@
+package argparse
+
+import (
+	"testing"
+)
+
+func TestThemeInherit(t *testing.T) {
+	c := struct {
+		Run *struct {
+			Force bool
+		}
+		Build *struct {
+			Force bool
+		}
+	}{}
+	parser := MustNew(&c)
+	theme := &Theme{Header: "1;4"}
+	parser.Theme = theme
+
+	build := parser.used_subcommand["build"].Subcommand
+	build.Theme = &Theme{Header: "32"}
+	if err := parser.Parse("build", "--force"); err != nil {
+		t.Fatalf("cannot parse build --force: %v", err)
+	} else if build.Theme.Header != "32" {
+		t.Errorf("the theme of the sub-command should be kept: %#v", build.Theme)
+	}
+
+	run := parser.used_subcommand["run"].Subcommand
+	if err := parser.Parse("run", "--force"); err != nil {
+		t.Fatalf("cannot parse run --force: %v", err)
+	} else if run.Theme != theme {
+		t.Errorf("the sub-command should inherit the theme: %#v", run.Theme)
+	}
+}
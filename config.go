@@ -27,6 +27,7 @@ const (
 	// the reserved key used in the structure
 	TAG_RESERVED_KEY = "args"
 	TAG_OPTION       = "option"
+	TAG_REMAINDER    = "remainder"
 
 	TAG_SHORTCUT    = "short"
 	TAG_NAME        = "name"
@@ -34,18 +35,78 @@ const (
 	TAG_CALLBACK    = "callback"
 	TAG_CHOICES     = "choices"
 	TAG_CHOICES_SEP = " "
+	TAG_ACTION      = "action"
+	TAG_NEGATE      = "negate"
+	TAG_ALIAS       = "alias"
+	TAG_ALIAS_SEP   = ","
+	// the alias which still works but not shown in the help message
+	TAG_HIDDEN_ALIAS = "hidden_alias"
+	// the deprecated message, and the version which the field is removed
+	TAG_DEPRECATED = "deprecated"
+	TAG_REMOVED    = "removed"
+	// the field not shown in the help message
+	TAG_HIDDEN = "hidden"
+	// the group of the option and sub-command shown in the help message
+	TAG_GROUP = "group"
+	// the mutually exclusive set of the options, only one can be passed
+	TAG_EXCLUSIVE = "exclusive"
 
 	TAG_DEFAULT_KEY = "default"
-	// the reserved key used in TAG_KEY
+	// the reserved key used in TAG_RESERVED_KEY, the secret option
 	KEY_PASSWORD = "password"
 	// default callback KEY
-	FN_HELP    = "_help"
-	FN_VERSION = "_version"
+	FN_HELP           = "_help"
+	FN_HELP_ALL       = "_help_all"
+	FN_HELP_COMMAND   = "_help_command"
+	FN_VERSION        = "_version"
+	FN_VERSION_FORMAT = "_version_format"
+	FN_DUMP_CONFIG    = "_dump_config"
 )
 
+// the action of the option when triggered, set by TAG_ACTION
+const (
+	// toggle the boolean value, the default action for the boolean option
+	ACTION_TOGGLE = "toggle"
+	// always set the boolean as true, and set false via --no-NAME
+	ACTION_SET = "set"
+	// increase the integer on each occurrence, like -vvv
+	ACTION_COUNT = "count"
+)
+
+// the format of the effective configuration dump
+const (
+	// the response file which can be fed back
+	DUMP_ARGS = "args"
+	DUMP_ENV  = "env"
+	DUMP_JSON = "json"
+	DUMP_YAML = "yaml"
+)
+
+// the format of the version message shown by --version
+const (
+	// the name and version, like "git (v1.0.0)"
+	VERSION_SHORT = "short"
+	// include the commit, build time and go version
+	VERSION_VERBOSE = "verbose"
+	VERSION_JSON    = "json"
+)
+
+// the provenance of the value in the configuration dump
+const (
+	SOURCE_DEFAULT  = "default"
+	SOURCE_ARGUMENT = "argument"
+)
+
+// the maximal depth of the nested response files
+const RESPONSE_MAX_DEPTH = 8
+
 // the default formatted string config
 const (
 	FMT_MARGIN  = 4
 	FMT_PENDING = 9
 	FMT_SIZE    = 24
+	// the minimal width of the wrapped help message
+	FMT_MIN_WRAP = 20
+	// the redacted secret shown in the help message and the log
+	SECRET_MASK = "******"
 )
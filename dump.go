@@ -0,0 +1,324 @@
+package argparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// the effective value of the field
+type ConfigEntry struct {
+	// the option name, or the lower-case name of the argument
+	Name string
+	// the plain value: bool, integer, string, list or nil, and the secret is redacted
+	Value interface{}
+	// the provenance of the value: SOURCE_DEFAULT or SOURCE_ARGUMENT
+	Source string
+	// the secret field, the value is redacted
+	Secret bool
+	// the arguments set the value, the secret is passed as "-" and prompted when fed back, or
+	// passed as the source of ArgParse.SecretSource
+	Args []string
+}
+
+// the effective configuration of the parser, include the selected sub-command
+type Config struct {
+	Name    string
+	Entries []ConfigEntry
+	// the arguments of the positional fields, include the remainder
+	Args []string
+	// the configuration of the selected sub-command
+	Command *Config
+}
+
+// the effective configuration from the current value, the secret is redacted and serialized as
+// the same as Args
+func (parser *ArgParse) Config() (config *Config, err error) {
+	config = &Config{Name: parser.Name}
+
+	for _, field := range parser.options {
+		if field.builtin() {
+			// the action is not the configuration
+			continue
+		}
+
+		var entry ConfigEntry
+		if entry, err = field.configEntry(); err != nil {
+			err = fmt.Errorf("--%v %v", field.Name, err)
+			return
+		} else if entry.Args, err = field.optionArgs(parser); err != nil {
+			err = fmt.Errorf("--%v %v", field.Name, err)
+			return
+		}
+		config.Entries = append(config.Entries, entry)
+	}
+
+	fields := parser.arguments
+	if parser.remainder != nil {
+		fields = append(fields[:len(fields):len(fields)], parser.remainder)
+	}
+	for _, field := range fields {
+		var entry ConfigEntry
+		if entry, err = field.configEntry(); err != nil {
+			err = fmt.Errorf("%v %v", field.Name, err)
+			return
+		}
+
+		entry.Name = strings.ToLower(entry.Name)
+		config.Entries = append(config.Entries, entry)
+	}
+
+	var selected *Field
+	if config.Args, selected, err = parser.positionalArgs(); err != nil || selected == nil {
+		return
+	}
+
+	sub, restore := selected.selectedSubcommand(parser)
+	defer restore()
+
+	if config.Command, err = sub.Config(); err != nil {
+		err = fmt.Errorf("%v %v", selected.Name, err)
+		return
+	}
+	config.Command.Name = selected.Name
+	return
+}
+
+// dump the effective configuration as the format: args, env, json or yaml
+func (parser *ArgParse) DumpConfig(format string) (str string, err error) {
+	var config *Config
+	if config, err = parser.Config(); err != nil {
+		return
+	}
+
+	str, err = config.Format(format)
+	return
+}
+
+// format the configuration as args (the response file), env, json or yaml
+func (config *Config) Format(format string) (str string, err error) {
+	switch format {
+	case DUMP_ARGS:
+		lines := []string{fmt.Sprintf("# the effective configuration of %v, the default is omitted", config.Name)}
+		if config.prompted() {
+			// cannot be fed back without the tty
+			lines = append(lines, "# the secret passed as - is prompted on the tty when fed back")
+		}
+		lines = append(lines, config.argsLines()...)
+		str = strings.Join(lines, "\n") + "\n"
+	case DUMP_ENV:
+		str = strings.Join(config.envLines(envName(config.Name)), "\n") + "\n"
+	case DUMP_JSON:
+		str = config.jsonObject("") + "\n"
+	case DUMP_YAML:
+		lines := append([]string{fmt.Sprintf("# the effective configuration of %v", config.Name)}, config.yamlLines("")...)
+		str = strings.Join(lines, "\n") + "\n"
+	default:
+		err = fmt.Errorf("unknown format: %v", format)
+	}
+	return
+}
+
+// the secret is passed as "-" which is prompted on the tty
+func (entry ConfigEntry) prompted() (ok bool) {
+	for _, arg := range entry.Args {
+		ok = ok || strings.HasSuffix(arg, "=-")
+	}
+	return
+}
+
+// any secret of the configuration and the selected sub-command is prompted
+func (config *Config) prompted() (ok bool) {
+	for _, entry := range config.Entries {
+		ok = ok || entry.Secret && entry.prompted()
+	}
+
+	if config.Command != nil {
+		ok = ok || config.Command.prompted()
+	}
+	return
+}
+
+// the lines of the response file, one option per line
+func (config *Config) argsLines() (lines []string) {
+	for _, entry := range config.Entries {
+		switch {
+		case len(entry.Args) == 0:
+		case entry.Secret && entry.prompted():
+			lines = append(lines, fmt.Sprintf("%v  # the secret is prompted", JoinArgs(entry.Args)))
+		default:
+			lines = append(lines, JoinArgs(entry.Args))
+		}
+	}
+
+	if len(config.Args) > 0 {
+		lines = append(lines, JoinArgs(config.Args))
+	}
+
+	if config.Command != nil {
+		lines = append(lines, quoteArg(config.Command.Name))
+		lines = append(lines, config.Command.argsLines()...)
+	}
+	return
+}
+
+// the KEY=value lines prefixed by the program and sub-command names, like GIT_REMOTE_ADD_NAME, and
+// the provenance is the comment line before the assignment
+func (config *Config) envLines(prefix string) (lines []string) {
+	for _, entry := range config.Entries {
+		name := fmt.Sprintf("%v_%v", prefix, envName(entry.Name))
+		lines = append(lines, fmt.Sprintf("# %v: %v", name, entry.Source))
+		lines = append(lines, fmt.Sprintf("%v=%v", name, envValue(entry.Value)))
+	}
+
+	if config.Command != nil {
+		lines = append(lines, config.Command.envLines(fmt.Sprintf("%v_%v", prefix, envName(config.Command.Name)))...)
+	}
+	return
+}
+
+// the JSON object of the entries in order, and the sub-command as the nested object
+func (config *Config) jsonObject(indent string) (str string) {
+	lines := []string{}
+	for _, entry := range config.Entries {
+		key, _ := json.Marshal(entry.Name)
+		value, _ := json.Marshal(entry.Value)
+		lines = append(lines, fmt.Sprintf(`%v    %s: {"value": %s, "source": %q}`, indent, key, value, entry.Source))
+	}
+
+	if config.Command != nil {
+		key, _ := json.Marshal(config.Command.Name)
+		lines = append(lines, fmt.Sprintf("%v    %s: %v", indent, key, config.Command.jsonObject(indent+"    ")))
+	}
+
+	if str = "{}"; len(lines) > 0 {
+		str = fmt.Sprintf("{\n%v\n%v}", strings.Join(lines, ",\n"), indent)
+	}
+	return
+}
+
+// the YAML lines of the entries with the provenance comment, the value is in the flow style
+func (config *Config) yamlLines(indent string) (lines []string) {
+	for _, entry := range config.Entries {
+		value, _ := json.Marshal(entry.Value)
+		lines = append(lines, fmt.Sprintf("%v%v: %s  # %v", indent, entry.Name, value, entry.Source))
+	}
+
+	if config.Command != nil {
+		lines = append(lines, fmt.Sprintf("%v%v:", indent, config.Command.Name))
+		lines = append(lines, config.Command.yamlLines(indent+"  ")...)
+	}
+	return
+}
+
+// the upper-case name of the environment variable, the non-alphanumeric is replaced by _
+func envName(name string) (env string) {
+	env = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return
+}
+
+// the value of the environment file, like docker --env-file and systemd EnvironmentFile, the list
+// is joined like the shell. The value is kept raw, or double quoted (only accepted by systemd) when
+// it cannot be raw, like the newline, the backslash and the surrounding space
+func envValue(value interface{}) (str string) {
+	switch v := value.(type) {
+	case nil:
+	case string:
+		str = v
+	case []interface{}:
+		elems := make([]string, len(v))
+		for idx, elem := range v {
+			elems[idx] = fmt.Sprint(elem)
+		}
+		str = JoinArgs(elems)
+	default:
+		str = fmt.Sprint(v)
+	}
+
+	if str != strings.TrimSpace(str) || strings.ContainsAny(str, "\n\\") || strings.IndexAny(str, `"'`) == 0 {
+		str = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(str) + `"`
+	}
+	return
+}
+
+// the entry of the field, the value is from the argument when set or not the default
+func (field *Field) configEntry() (entry ConfigEntry, err error) {
+	entry = ConfigEntry{Name: field.Name, Source: SOURCE_DEFAULT, Secret: field.Secret}
+
+	var initial interface{}
+	if entry.Value, err = plainValue(field.Value); err != nil {
+		return
+	} else if initial, err = plainValue(field.initial); err != nil {
+		return
+	}
+
+	if field.BeenSet || !reflect.DeepEqual(entry.Value, initial) {
+		entry.Source = SOURCE_ARGUMENT
+	}
+
+	if field.Secret && entry.Value != nil && entry.Value != "" {
+		// never show the secret
+		entry.Value = SECRET_MASK
+	}
+	return
+}
+
+// the plain value used in the configuration dump: bool, integer, string, list or nil
+func plainValue(value reflect.Value) (plain interface{}, err error) {
+	switch {
+	case value.Kind() == reflect.Ptr && value.Type() != fileType:
+		if !value.IsNil() {
+			plain, err = plainValue(value.Elem())
+		}
+		return
+	case value.Kind() == reflect.Slice && value.Type() != ipType:
+		if value.IsNil() {
+			return
+		}
+
+		list := []interface{}{}
+		for idx := 0; idx < value.Len(); idx++ {
+			var elem interface{}
+			if elem, err = plainValue(value.Index(idx)); err != nil {
+				return
+			}
+			list = append(list, elem)
+		}
+		plain = list
+		return
+	}
+
+	var token string
+	switch value.Interface().(type) {
+	case *os.File, time.Time, net.Interface, net.IP, net.IPNet:
+		// the string form same as the argument
+		token, err = formatValue(value)
+		plain = token
+	default:
+		switch value.Kind() {
+		case reflect.Bool:
+			plain = value.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			plain = value.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			plain = value.Uint()
+		default:
+			token, err = formatValue(value)
+			plain = token
+		}
+	}
+	return
+}
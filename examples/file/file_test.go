@@ -1,8 +1,12 @@
 package main
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cmj0121/argparse"
 )
@@ -15,11 +19,11 @@ func ExampleFile() {
 	parser := argparse.MustNew(&c)
 	parser.Parse("-h")
 	// Output:
-	// usage: file [OPTION] ACTION
+	// usage: file [OPTIONS] [ACTION] [<COMMAND>]
 	//
 	// option:
 	//          -h, --help                  show this message
-	//          -v, --version               show argparse version
+	//          -v, --version               show the version
 	//     -m PERM, --filemode PERM         file perm (default: --wxrw--wx)
 	//     -c TIME, --created_at TIME       timestamp RFC-3339 (2006-01-02T15:04:05+07:00)
 	//      -p STR, --path STR              file path list
@@ -39,3 +43,94 @@ func TestFile(t *testing.T) {
 		t.Fatalf("cannot parse -c 2020-01-02T11:22:33+07:00: %v", err)
 	}
 }
+
+func ExampleFilePrompt() {
+	argparse.Stderr = os.Stdout
+
+	stdin, _ := ioutil.TempFile("", "argparse")
+	defer os.Remove(stdin.Name())
+	stdin.WriteString("\nfas\n2\nabc\n7\n1 x\n1 2 3\n")
+	stdin.Seek(0, 0)
+
+	defer func(f *os.File) { argparse.Stdin = f }(argparse.Stdin)
+	argparse.Stdin = stdin
+
+	c := struct {
+		Mode  *string `choices:"fast slow" help:"the copy mode"`
+		Count *int
+		Ports *[]int
+	}{}
+	parser := argparse.MustNew(&c)
+	parser.PromptMissing()
+	fmt.Println(*c.Mode, *c.Count, *c.Ports)
+	// Output:
+	// the copy mode [MODE]:
+	//     1) fast
+	//     2) slow
+	// > the copy mode [MODE]:
+	//     1) fast
+	//     2) slow
+	// > error: fas should choice from [fast slow], did you mean fast?
+	// the copy mode [MODE]:
+	//     1) fast
+	//     2) slow
+	// > COUNT:
+	// > error: should pass INT: abc
+	// COUNT:
+	// > PORTS:
+	// > error: cannot set []int: should pass INT: x
+	// PORTS:
+	// > slow 7 [1 2 3]
+}
+
+func TestFileArgs(t *testing.T) {
+	file, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	c := File{}
+	parser := argparse.MustNew(&c)
+
+	action := "copy a b"
+	c.FileMode = 0644
+	c.CreatedAt = time.Date(2020, 1, 2, 11, 22, 33, 123456789, time.FixedZone("", 7*3600))
+	c.Path = []string{"a", "b c"}
+	c.Action = &action
+	c.Sub = &FileAction{File: file, Action: &action}
+
+	args, err := parser.Args()
+	if err != nil {
+		t.Fatalf("cannot serialize: %v", err)
+	} else if ans := []string{
+		"--filemode=420", "--created_at=2020-01-02T11:22:33.123456789+07:00", "--path=a", "--path=b c",
+		"copy a b", "sub", "--file=" + file.Name(), "copy a b",
+	}; !reflect.DeepEqual(args, ans) {
+		t.Errorf("serialize: %#v", args)
+	}
+
+	d := File{}
+	if err := argparse.MustNew(&d).ParseString(argparse.JoinArgs(args)); err != nil {
+		t.Fatalf("cannot parse %v: %v", argparse.JoinArgs(args), err)
+	}
+	defer d.Sub.File.Close()
+
+	switch {
+	case d.FileMode != c.FileMode:
+		t.Errorf("round trip os.FileMode: %v", d.FileMode)
+	case !d.CreatedAt.Equal(c.CreatedAt):
+		t.Errorf("round trip time.Time: %v", d.CreatedAt)
+	case !reflect.DeepEqual(d.Path, c.Path) || *d.Action != action:
+		t.Errorf("round trip: %#v %v", d.Path, *d.Action)
+	case d.FileAction != nil || d.Sub == nil || d.Sub.File.Name() != file.Name() || *d.Sub.Action != action:
+		t.Errorf("round trip sub-command: %#v %#v", d.FileAction, d.Sub)
+	}
+
+	flag := "-x"
+	c.Action = &flag
+	if _, err := parser.Args(); err == nil || err.Error() != `cannot serialize the argument "-x" before the sub-command or remainder` {
+		t.Errorf("expect the argument like option failure: %v", err)
+	}
+}
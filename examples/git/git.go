@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cmj0121/argparse"
+)
+
+type Status struct {
+	argparse.Help
+
+	Short bool `short:"s" help:"show the status in short format"`
+}
+
+type Stash struct {
+	argparse.Help
+
+	Message string `short:"m" help:"the stash message"`
+}
+
+type Remove struct {
+	argparse.Help
+
+	Cached bool `help:"only remove from the index"`
+	Path   *[]string
+}
+
+type Add struct {
+	argparse.Help
+
+	Name *string `help:"the name of remote"`
+	URL  *string `name:"url" help:"the URL of remote"`
+}
+
+type Remote struct {
+	argparse.Help
+
+	*Add `help:"add the remote"`
+}
+
+func (remote Remote) Description() string {
+	return "Manage the set of repositories whose branches you track."
+}
+
+func (remote Remote) Examples() []argparse.Example {
+	return []argparse.Example{
+		{Command: "add origin https://github.com/cmj0121/argparse", Description: "add the remote named origin"},
+	}
+}
+
+type Git struct {
+	argparse.Help
+
+	Verbose int    `short:"v" action:"count" help:"be more verbose"`
+	Stat    string `help:"show the statistic"`
+	Verify  bool   `help:"verify the signature"`
+
+	*Status `help:"show the working tree status"`
+	*Stash  `help:"stash the changes in a dirty working directory\nsee also the stash list"`
+	*Remove `alias:"rm" help:"remove files from the working tree"`
+	*Remote `help:"manage the remote repositories"`
+
+	argparse.HelpCommand
+}
+
+func (git Git) Description() string {
+	return "The stupid content tracker, a fast, scalable and distributed revision control system."
+}
+
+func (git Git) Epilog() string {
+	return "Report bugs to https://github.com/cmj0121/argparse/issues"
+}
+
+func main() {
+	c := Git{}
+	parser := argparse.MustNew(&c)
+	parser.AllowPrefix = true
+	if err := parser.Run(); err == nil {
+		data, _ := json.MarshalIndent(c, "", "    ")
+		fmt.Println(string(data))
+	}
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cmj0121/argparse"
+)
+
+func ExampleGit() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	c := Git{}
+	parser := argparse.MustNew(&c)
+	parser.Parse("-h")
+	// Output:
+	// usage: git [OPTIONS] [<COMMAND>]
+	//
+	// The stupid content tracker, a fast, scalable and distributed revision control system.
+	//
+	// option:
+	//          -h, --help                  show this message
+	//          -v, --verbose               be more verbose
+	//              --stat STR              show the statistic
+	//              --[no-]verify           verify the signature
+	//
+	// sub-command:
+	//     status                           show the working tree status
+	//     stash                            stash the changes in a dirty working directory
+	//                                      see also the stash list
+	//     remove                           remove files from the working tree (alias: rm)
+	//     remote                           manage the remote repositories
+	//     help                             show the help message of the command
+	//
+	// Report bugs to https://github.com/cmj0121/argparse/issues
+}
+
+func ExampleGitRemote() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	c := Git{}
+	parser := argparse.MustNew(&c)
+	parser.Parse("remote", "-h")
+	// Output:
+	// usage: git remote [OPTIONS] [<COMMAND>]
+	//
+	// Manage the set of repositories whose branches you track.
+	//
+	// option:
+	//          -h, --help                  show this message
+	//
+	// sub-command:
+	//     add                              add the remote
+	//
+	// example:
+	//     git remote add origin https://github.com/cmj0121/argparse
+	//         add the remote named origin
+}
+
+func TestGitAlias(t *testing.T) {
+	c := Git{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("rm", "--cached", "a", "b"); err != nil {
+		t.Fatalf("cannot parse rm --cached a b: %v", err)
+	} else if c.Remove == nil || !c.Remove.Cached {
+		t.Errorf("parse rm --cached: %#v", c.Remove)
+	} else if ans := []string{"a", "b"}; !reflect.DeepEqual(*c.Remove.Path, ans) {
+		t.Errorf("parse rm a b: %#v", c.Remove.Path)
+	}
+
+	if err := parser.Parse("stat"); err == nil {
+		t.Fatalf("expect stat failure without prefix")
+	}
+}
+
+func TestGitPrefix(t *testing.T) {
+	c := Git{}
+	parser := argparse.MustNew(&c)
+	parser.AllowPrefix = true
+
+	if err := parser.Parse("--verb", "--stat", "x", "stat", "--sh"); err != nil {
+		t.Fatalf("cannot parse --verb --stat x stat --sh: %v", err)
+	} else if c.Verbose != 1 || c.Stat != "x" {
+		t.Errorf("parse --verb --stat x: %v %v", c.Verbose, c.Stat)
+	} else if c.Status == nil || !c.Status.Short {
+		t.Errorf("parse stat --sh: %#v", c.Status)
+	}
+
+	if err := parser.Parse("--no-ver"); err != nil {
+		t.Fatalf("cannot parse --no-ver: %v", err)
+	} else if err := parser.Parse("--ver"); err == nil {
+		t.Fatalf("expect --ver failure")
+	} else if ans := "ambiguous --ver: did you mean --verbose or --verify?"; err.Error() != ans {
+		t.Errorf("ambiguous --ver: %v", err)
+	}
+
+	if err := parser.Parse("sta"); err == nil {
+		t.Fatalf("expect sta failure")
+	} else if ans := "ambiguous sta: did you mean stash or status?"; err.Error() != ans {
+		t.Errorf("ambiguous sta: %v", err)
+	}
+}
+
+func TestGitPrefixHidden(t *testing.T) {
+	c := struct {
+		Verbose  bool `hidden_alias:"verb"`
+		Verify   bool
+		Verbatim bool `hidden:"true"`
+	}{}
+	parser := argparse.MustNew(&c)
+	parser.AllowPrefix = true
+
+	if err := parser.Parse("--ver"); err == nil {
+		t.Fatalf("expect --ver failure")
+	} else if ans := "ambiguous --ver: did you mean --verbose or --verify?"; err.Error() != ans {
+		t.Errorf("ambiguous --ver should not show the hidden name: %v", err)
+	}
+
+	if err := parser.Parse("--verba"); err == nil {
+		t.Fatalf("expect --verba failure")
+	} else if err := parser.Parse("--verbatim", "--verb"); err != nil {
+		t.Fatalf("cannot parse --verbatim --verb: %v", err)
+	} else if !c.Verbatim || !c.Verbose {
+		t.Errorf("parse --verbatim --verb: %v %v", c.Verbatim, c.Verbose)
+	}
+}
+
+func TestGitPromoted(t *testing.T) {
+	c := struct {
+		*Remote
+	}{}
+	if parser := argparse.MustNew(&c); parser.Description != "" || len(parser.Examples) != 0 {
+		t.Errorf("the promoted method should be skipped: %#v %#v", parser.Description, parser.Examples)
+	}
+
+	c.Remote = &Remote{}
+	if parser := argparse.MustNew(&c); parser.Description != "" || len(parser.Examples) != 0 {
+		t.Errorf("the promoted method should be skipped: %#v %#v", parser.Description, parser.Examples)
+	}
+
+	g := Git{Remote: &Remote{}}
+	if parser := argparse.MustNew(&g); parser.Description != g.Description() {
+		t.Errorf("the declared method should shadow the embedded: %#v", parser.Description)
+	}
+
+	same := Shadow{}
+	if parser := argparse.MustNew(&same); parser.Description != (Remote{}).Description() {
+		t.Errorf("the declared method same as the embedded should be kept: %#v", parser.Description)
+	}
+}
+
+// the structure declares the same description as the embedded sub-command
+type Shadow struct {
+	*Remote
+}
+
+func (Shadow) Description() string {
+	return (Remote{}).Description()
+}
+
+func TestGitSuggest(t *testing.T) {
+	c := Git{}
+	parser := argparse.MustNew(&c)
+
+	cases := map[string][]string{
+		"unknown option: --verbos, did you mean --verbose?": {"--verbos"},
+		"unknown option: --verfy, did you mean --verify?":   {"--verfy"},
+		"unknown option: -V, did you mean -v?":              {"-V"},
+		"unknown option: -e, did you mean --verbose?":       {"-verbose"},
+		"unknown argument: stauts, did you mean status?":    {"stauts"},
+		"unknown argument: sta, did you mean stash?":        {"sta"},
+		"unknown argument: remot, did you mean remote?":     {"remot"},
+		"unknown argument: xyz":                             {"xyz"},
+	}
+
+	for ans, args := range cases {
+		if err := parser.Parse(args...); err == nil {
+			t.Errorf("expect %v failure", args)
+		} else if err.Error() != ans {
+			t.Errorf("parse %v: %v", args, err)
+		}
+	}
+
+	argparse.SuggestThreshold = 0
+	defer func() { argparse.SuggestThreshold = 2 }()
+	if err := parser.Parse("--verbos"); err == nil || err.Error() != "unknown option: --verbos" {
+		t.Errorf("parse --verbos without suggestion: %v", err)
+	}
+}
+
+func ExampleGitWrap() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	argparse.HelpWidth = 64
+	defer func() { argparse.HelpWidth = 0 }()
+
+	c := Git{}
+	parser := argparse.MustNew(&c)
+	parser.Parse("-h")
+	// Output:
+	// usage: git [OPTIONS] [<COMMAND>]
+	//
+	// The stupid content tracker, a fast, scalable and distributed
+	// revision control system.
+	//
+	// option:
+	//          -h, --help                  show this message
+	//          -v, --verbose               be more verbose
+	//              --stat STR              show the statistic
+	//              --[no-]verify           verify the signature
+	//
+	// sub-command:
+	//     status                           show the working tree
+	//                                      status
+	//     stash                            stash the changes in a
+	//                                      dirty working directory
+	//                                      see also the stash list
+	//     remove                           remove files from the
+	//                                      working tree (alias: rm)
+	//     remote                           manage the remote
+	//                                      repositories
+	//     help                             show the help message of
+	//                                      the command
+	//
+	// Report bugs to https://github.com/cmj0121/argparse/issues
+}
+
+func ExampleGitTemplate() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	c := Git{}
+	parser := argparse.MustNew(&c)
+	parser.HelpTemplate = `{{.Program}} - {{.Description}}
+{{range .Sections}}[{{upper .Title}}]
+{{range .Entries}}  {{if .Shortcut}}-{{.Shortcut}}, {{end}}{{.Name}}{{with .Hint}} <{{.}}>{{end}}: {{.Help}}
+{{end}}{{end}}`
+	parser.Parse("remote", "-h")
+	// Output:
+	// git remote - Manage the set of repositories whose branches you track.
+	// [OPTION]
+	//   -h, help: show this message
+	// [SUB-COMMAND]
+	//   add: add the remote
+}
+
+func ExampleGitHelpCommand() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	c := Git{}
+	parser := argparse.MustNew(&c)
+	parser.Parse("help", "remote", "add")
+	parser.Parse("help", "remote", "ad")
+	// Output:
+	// usage: git remote add [OPTIONS] [NAME] [URL]
+	//
+	// option:
+	//          -h, --help                  show this message
+	//
+	// argument:
+	//     NAME                             the name of remote
+	//     URL                              the URL of remote
+	// error: unknown command: ad, did you mean add?
+	// usage: git remote [OPTIONS] [<COMMAND>]
+	//
+	// Manage the set of repositories whose branches you track.
+	//
+	// option:
+	//          -h, --help                  show this message
+	//
+	// sub-command:
+	//     add                              add the remote
+	//
+	// example:
+	//     git remote add origin https://github.com/cmj0121/argparse
+	//         add the remote named origin
+}
+
+func TestGitShell(t *testing.T) {
+	stdin, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(stdin.Name())
+	stdin.WriteString("status -s\n\nremote add origin 'https://example.com/a b'\nbogus\nstash -m \"say \\\"hi\\\"\" 'oops\nstatus --help\nhelp remote\nhistory\nexit\nstatus\n")
+	stdin.Seek(0, 0)
+
+	stderr, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(stderr.Name())
+
+	defer func(f *os.File) { argparse.Stdin = f }(argparse.Stdin)
+	defer func(f *os.File) { argparse.Stderr = f }(argparse.Stderr)
+	argparse.Stdin, argparse.Stderr = stdin, stderr
+
+	c := Git{}
+	parser := argparse.MustNew(&c)
+
+	executed := []string{}
+	if err := parser.Shell(func(in interface{}) error {
+		git := in.(*Git)
+		switch {
+		case git.Status != nil:
+			executed = append(executed, fmt.Sprintf("status %v", git.Status.Short))
+		case git.Remote != nil && git.Remote.Add != nil:
+			executed = append(executed, fmt.Sprintf("remote add %v", *git.Remote.Add.URL))
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("cannot run shell: %v", err)
+	}
+
+	if ans := []string{"status true", "remote add https://example.com/a b"}; !reflect.DeepEqual(executed, ans) {
+		t.Errorf("shell should execute with the reset value: %#v", executed)
+	}
+
+	data, _ := ioutil.ReadFile(stderr.Name())
+	for _, msg := range []string{
+		"error: unknown argument: bogus",
+		"error: unterminated quote ' at column 23",
+		"usage: git status [OPTIONS]",
+		"usage: git remote [OPTIONS] [<COMMAND>]",
+		"    6  history",
+	} {
+		if !strings.Contains(string(data), msg) {
+			t.Errorf("shell output should contain %#v: %v", msg, string(data))
+		}
+	}
+
+	if words := parser.Complete("remote"); !reflect.DeepEqual(words, []string{"--help", "add"}) {
+		t.Errorf("complete remote: %#v", words)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/cmj0121/argparse"
+)
+
+type Network struct {
+	Host net.IP `short:"H" help:"the host address"`
+	Port int    `short:"p" default:"8080" help:"the listen port"`
+}
+
+type Auth struct {
+	User     string `short:"u" help:"the user name"`
+	Password string `help:"the password of the user"`
+}
+
+type Command struct {
+	argparse.Help
+}
+
+type Group struct {
+	argparse.Help
+
+	Network Network `help:"the network settings"`
+	Auth    `group:"Authentication"`
+
+	Format string `group:"Output" choices:"json yaml" help:"the output format"`
+	Quiet  bool   `short:"q" group:"Output" help:"suppress the output"`
+
+	Run   *Command `group:"Core commands" help:"run the service"`
+	Stop  *Command `group:"Core commands" help:"stop the service"`
+	Image *Command `group:"Management commands" help:"manage the images"`
+	Login *Command `help:"login to the registry"`
+}
+
+func main() {
+	c := Group{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Run(); err == nil {
+		data, _ := json.MarshalIndent(c, "", "    ")
+		fmt.Println(string(data))
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cmj0121/argparse"
+)
+
+func ExampleGroup() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	c := Group{}
+	parser := argparse.MustNew(&c)
+	parser.Parse("-h")
+	// Output:
+	// usage: group [OPTIONS] [<COMMAND>]
+	//
+	// option:
+	//          -h, --help                  show this message
+	//
+	// Network:
+	//     the network settings
+	//       -H IP, --host IP               the host address
+	//      -p INT, --port INT              the listen port (default: 8080)
+	//
+	// Authentication:
+	//      -u STR, --user STR              the user name
+	//              --password STR          the password of the user
+	//
+	// Output:
+	//              --format STR            the output format [json yaml]
+	//          -q, --[no-]quiet            suppress the output
+	//
+	// sub-command:
+	//     login                            login to the registry
+	//
+	// Core commands:
+	//     run                              run the service
+	//     stop                             stop the service
+	//
+	// Management commands:
+	//     image                            manage the images
+}
+
+func ExampleGroupOrder() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	c := Group{}
+	parser := argparse.MustNew(&c)
+	parser.SetGroup("Authentication", "the credential of the registry")
+	parser.SetGroupOrder("Output", "Management commands")
+	parser.Parse("-h")
+	// Output:
+	// usage: group [OPTIONS] [<COMMAND>]
+	//
+	// option:
+	//          -h, --help                  show this message
+	//
+	// Output:
+	//              --format STR            the output format [json yaml]
+	//          -q, --[no-]quiet            suppress the output
+	//
+	// Network:
+	//     the network settings
+	//       -H IP, --host IP               the host address
+	//      -p INT, --port INT              the listen port (default: 8080)
+	//
+	// Authentication:
+	//     the credential of the registry
+	//      -u STR, --user STR              the user name
+	//              --password STR          the password of the user
+	//
+	// sub-command:
+	//     login                            login to the registry
+	//
+	// Management commands:
+	//     image                            manage the images
+	//
+	// Core commands:
+	//     run                              run the service
+	//     stop                             stop the service
+}
+
+func TestGroup(t *testing.T) {
+	c := Group{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("-H", "127.0.0.1", "-p", "80", "-u", "user", "--format", "json", "-q", "stop"); err != nil {
+		t.Fatalf("cannot parse grouped options: %v", err)
+	} else {
+		if c.Network.Host.String() != "127.0.0.1" || c.Network.Port != 80 {
+			t.Errorf("parse -H 127.0.0.1 -p 80: %v %v", c.Network.Host, c.Network.Port)
+		}
+		if c.Auth.User != "user" {
+			t.Errorf("parse -u user: %v", c.Auth.User)
+		}
+		if c.Format != "json" || !c.Quiet {
+			t.Errorf("parse --format json -q: %v %v", c.Format, c.Quiet)
+		}
+		if c.Stop == nil {
+			t.Errorf("parse stop: %v", c.Stop)
+		}
+	}
+}
+
+func TestGroupExclusive(t *testing.T) {
+	c := struct {
+		Verbose bool
+		JSON    bool   `name:"json" exclusive:"format"`
+		YAML    bool   `name:"yaml" exclusive:"format"`
+		Output  string `short:"o" exclusive:"format"`
+	}{}
+	parser := argparse.MustNew(&c)
+	parser.Name = "fmt"
+	if usage := parser.HelpModel(nil, false).Usage; usage != "usage: fmt [OPTIONS] [--json | --yaml | --output STR]" {
+		t.Errorf("usage with the exclusive options: %#v", usage)
+	}
+
+	if err := parser.Parse("--json", "--verbose", "--json"); err != nil {
+		t.Fatalf("cannot parse --json --verbose --json: %v", err)
+	} else if err := parser.Parse("--yaml"); err != nil {
+		t.Fatalf("cannot parse --yaml in the next parse: %v", err)
+	}
+
+	if err := parser.Parse("--json", "-o", "x"); err == nil || err.Error() != "-o --output cannot be used with --json" {
+		t.Errorf("expect the exclusive failure: %v", err)
+	}
+
+	invalid := struct {
+		Path *string `exclusive:"format"`
+	}{}
+	if _, err := argparse.New(&invalid); err == nil {
+		t.Fatalf("expect the exclusive argument failure")
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"net"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/cmj0121/argparse"
@@ -15,7 +17,7 @@ func ExampleIFace() {
 	parser := argparse.MustNew(&c)
 	parser.Parse("-h")
 	// Output:
-	// usage: iface [OPTION] IFACE
+	// usage: iface [OPTIONS] [IFACE]
 	//
 	// option:
 	//          -h, --help                  show this message
@@ -56,3 +58,37 @@ func TestIFace(t *testing.T) {
 		t.Fatalf("cannot parse --inet github.com/16: %v", err)
 	}
 }
+
+func TestIFaceArgs(t *testing.T) {
+	c := IFace{}
+	parser := argparse.MustNew(&c)
+
+	_, inet, _ := net.ParseCIDR("192.168.0.0/16")
+	c.IP, c.INet = net.ParseIP("fe80::1"), *inet
+	if ifaces, err := net.Interfaces(); err == nil && len(ifaces) > 0 {
+		c.IFace, c.Interface = &ifaces[0], &ifaces[0]
+	}
+
+	args, err := parser.Args()
+	if err != nil {
+		t.Fatalf("cannot serialize: %v", err)
+	} else if ans := []string{"--ip=fe80::1", "--inet=192.168.0.0/16"}; c.IFace == nil && !reflect.DeepEqual(args, ans) {
+		t.Errorf("serialize: %#v", args)
+	} else if c.IFace != nil && !reflect.DeepEqual(args, []string{"--iface=" + c.IFace.Name, ans[0], ans[1], c.IFace.Name}) {
+		t.Errorf("serialize: %#v", args)
+	}
+
+	d := IFace{}
+	if err := argparse.MustNew(&d).Parse(args...); err != nil {
+		t.Fatalf("cannot parse %v: %v", args, err)
+	}
+
+	switch {
+	case !d.IP.Equal(c.IP):
+		t.Errorf("round trip net.IP: %v", d.IP)
+	case d.INet.String() != c.INet.String():
+		t.Errorf("round trip net.IPNet: %v", d.INet)
+	case c.IFace != nil && (d.IFace.Name != c.IFace.Name || d.Interface.Name != c.Interface.Name):
+		t.Errorf("round trip net.Interface: %v %v", d.IFace, d.Interface)
+	}
+}
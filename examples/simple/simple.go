@@ -9,6 +9,7 @@ import (
 
 type Simple struct {
 	argparse.Model
+	argparse.HelpAll
 
 	// the ignore field that will not be processed
 	Ignore bool `-`
@@ -20,9 +21,16 @@ type Simple struct {
 	// the option can be set repeatedly by-default
 	Switch bool   `short:"s" name:"toggle" help:"toggle the boolean value"`
 	Count  int    `short:"C" help:"save as the integer"`
-	Name   string `name:"user-name"`
+	Name   string `name:"user-name" alias:"user,login,u" hidden_alias:"username"`
 	Cases  string `short:"c" choices:"demo foo" help:"choice from fix possible"`
 
+	// the counter and the idempotent switch
+	Verbose int  `short:"V" action:"count" help:"increase the verbosity"`
+	Force   bool `short:"f" action:"set" help:"always set as true"`
+
+	// the internal switch not shown in the help message
+	Debug bool `hidden:"true" help:"show the debug message"`
+
 	Path *[]string `help:"multi-argument"`
 }
 
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/cmj0121/argparse"
@@ -16,15 +22,18 @@ func ExampleSimple() {
 	parser := argparse.MustNew(&c)
 	parser.Parse("-h")
 	// Output:
-	// usage: simple [OPTION] PATH
+	// usage: simple [OPTIONS] [PATH...]
 	//
 	// option:
 	//          -h, --help                  show this message
-	//          -v, --version               show argparse version
-	//          -s, --toggle                toggle the boolean value
+	//          -v, --version               show the version
+	//              --help-all              show this message include hidden
+	//          -s, --[no-]toggle           toggle the boolean value
 	//      -C INT, --count INT             save as the integer
-	//              --user-name STR
+	//              --user-name STR         (alias: --user, --login, -u)
 	//      -c STR, --cases STR             choice from fix possible [demo foo]
+	//          -V, --verbose               increase the verbosity
+	//          -f, --[no-]force            always set as true
 	//
 	// argument:
 	//     PATH                             multi-argument
@@ -46,15 +55,44 @@ func ExampleSimpleDefault() {
 	parser := argparse.MustNew(&c)
 	parser.Parse("-h")
 	// Output:
-	// usage: simple [OPTION] PATH
+	// usage: simple [OPTIONS] [PATH...]
 	//
 	// option:
 	//          -h, --help                  show this message
-	//          -v, --version               show argparse version
-	//          -s, --toggle                toggle the boolean value
+	//          -v, --version               show the version
+	//              --help-all              show this message include hidden
+	//          -s, --[no-]toggle           toggle the boolean value
 	//      -C INT, --count INT             save as the integer (default: 123)
-	//              --user-name STR         (default: simple)
+	//              --user-name STR         (alias: --user, --login, -u) (default: simple)
 	//      -c STR, --cases STR             choice from fix possible [demo foo] (default: demo)
+	//          -V, --verbose               increase the verbosity
+	//          -f, --[no-]force            always set as true
+	//
+	// argument:
+	//     PATH                             multi-argument
+}
+
+func ExampleSimpleHelpAll() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	c := Simple{}
+	parser := argparse.MustNew(&c)
+	parser.Parse("--help-all")
+	// Output:
+	// usage: simple [OPTIONS] [PATH...]
+	//
+	// option:
+	//          -h, --help                  show this message
+	//          -v, --version               show the version
+	//              --help-all              show this message include hidden
+	//          -s, --[no-]toggle           toggle the boolean value
+	//      -C INT, --count INT             save as the integer
+	//              --user-name STR         (alias: --user, --login, -u)
+	//      -c STR, --cases STR             choice from fix possible [demo foo]
+	//          -V, --verbose               increase the verbosity
+	//          -f, --[no-]force            always set as true
+	//              --[no-]debug            show the debug message (hidden)
 	//
 	// argument:
 	//     PATH                             multi-argument
@@ -108,3 +146,527 @@ func TestSimple(t *testing.T) {
 		}
 	}
 }
+
+func TestSimpleAction(t *testing.T) {
+	c := Simple{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("-VVV", "-V", "--verbose"); err != nil {
+		t.Fatalf("cannot parse -VVV -V --verbose: %v", err)
+	} else if c.Verbose != 5 {
+		t.Errorf("parse -VVV -V --verbose: %v", c.Verbose)
+	}
+
+	if err := parser.Parse("-ff", "--force"); err != nil {
+		t.Fatalf("cannot parse -ff --force: %v", err)
+	} else if !c.Force {
+		t.Errorf("parse -ff --force: %v", c.Force)
+	}
+
+	if err := parser.Parse("--no-force"); err != nil {
+		t.Fatalf("cannot parse --no-force: %v", err)
+	} else if c.Force {
+		t.Errorf("parse --no-force: %v", c.Force)
+	}
+
+	if err := parser.Parse("--no-help"); err == nil {
+		t.Fatalf("expect --no-help failure")
+	}
+}
+
+func TestSimpleBoolean(t *testing.T) {
+	c := Simple{Switch: true}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("--no-toggle"); err != nil {
+		t.Fatalf("cannot parse --no-toggle: %v", err)
+	} else if c.Switch {
+		t.Errorf("parse --no-toggle: %v", c.Switch)
+	}
+
+	for _, value := range []string{"true", "yes", "1", "on", "ON"} {
+		c.Switch = false
+		if err := parser.Parse("--toggle=" + value); err != nil {
+			t.Fatalf("cannot parse --toggle=%v: %v", value, err)
+		} else if !c.Switch {
+			t.Errorf("parse --toggle=%v: %v", value, c.Switch)
+		}
+	}
+
+	for _, value := range []string{"false", "no", "0", "off", "False"} {
+		c.Switch = true
+		if err := parser.Parse("--toggle=" + value); err != nil {
+			t.Fatalf("cannot parse --toggle=%v: %v", value, err)
+		} else if c.Switch {
+			t.Errorf("parse --toggle=%v: %v", value, c.Switch)
+		}
+	}
+
+	if err := parser.Parse("--toggle=abc"); err == nil {
+		t.Fatalf("expect --toggle=abc failure")
+	} else if err := parser.Parse("--no-toggle=true"); err == nil {
+		t.Fatalf("expect --no-toggle=true failure")
+	} else if err := parser.Parse("--verbose=3"); err == nil {
+		t.Fatalf("expect --verbose=3 failure")
+	}
+
+	if err := parser.Parse("--count=42", "--user-name=a=b"); err != nil {
+		t.Fatalf("cannot parse --count=42 --user-name=a=b: %v", err)
+	} else if c.Count != 42 || c.Name != "a=b" {
+		t.Errorf("parse --count=42 --user-name=a=b: %v %v", c.Count, c.Name)
+	}
+}
+
+func TestSimpleExplicitCallback(t *testing.T) {
+	stderr, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(stderr.Name())
+	defer func(f *os.File) { argparse.Stderr = f }(argparse.Stderr)
+	argparse.Stderr = stderr
+	argparse.ExitWhenCallback = false
+
+	c := Simple{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("--help=false", "--count=42"); err != nil {
+		t.Fatalf("cannot parse --help=false --count=42: %v", err)
+	} else if c.Count != 42 {
+		t.Errorf("--help=false should not stop parsing: %v", c.Count)
+	}
+
+	if data, _ := ioutil.ReadFile(stderr.Name()); len(data) > 0 {
+		t.Errorf("--help=false should not show the help: %#v", string(data))
+	}
+}
+
+func TestSimpleAlias(t *testing.T) {
+	c := Simple{}
+	parser := argparse.MustNew(&c)
+	for _, option := range []string{"--user-name", "--user", "--login", "-u", "--username"} {
+		if err := parser.Parse(option, option); err != nil {
+			t.Fatalf("cannot parse %v: %v", option, err)
+		} else if c.Name != option {
+			t.Errorf("parse %v: %v", option, c.Name)
+		}
+	}
+
+	duplicated := struct {
+		Name string `alias:"user"`
+		User string
+	}{}
+	if _, err := argparse.New(&duplicated); err == nil {
+		t.Fatalf("expect duplicated alias failure")
+	}
+
+	shortcut := struct {
+		Name string `short:"n"`
+		User string `alias:"n"`
+	}{}
+	if _, err := argparse.New(&shortcut); err == nil {
+		t.Fatalf("expect duplicated shortcut failure")
+	}
+
+	negative := struct {
+		Color bool `alias:"colour" hidden_alias:"colr"`
+	}{Color: true}
+	parser = argparse.MustNew(&negative)
+	if err := parser.Parse("--no-colour"); err != nil {
+		t.Fatalf("cannot parse --no-colour: %v", err)
+	} else if negative.Color {
+		t.Errorf("parse --no-colour: %v", negative.Color)
+	} else if err := parser.Parse("--no-colr"); err == nil {
+		t.Fatalf("expect --no-colr of the hidden alias failure")
+	}
+}
+
+func TestSimpleUsage(t *testing.T) {
+	src := "a.txt"
+	c := struct {
+		Quiet bool
+		Src   *string
+		Dst   *[]string
+	}{
+		Src: &src,
+	}
+	parser := argparse.MustNew(&c)
+	parser.Name = "cp"
+	if usage := parser.HelpModel(nil, false).Usage; usage != "usage: cp [OPTIONS] [SRC] [DST...]" {
+		t.Errorf("usage with optional argument: %#v", usage)
+	}
+}
+
+func TestSimpleDeprecated(t *testing.T) {
+	stderr, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(stderr.Name())
+	defer func(f *os.File) { argparse.Stderr = f }(argparse.Stderr)
+	argparse.Stderr = stderr
+
+	c := struct {
+		User     string
+		UserName string `name:"user-name" deprecated:"use --user instead" removed:"2.0.0"`
+	}{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("--user-name", "a", "--user-name", "b"); err != nil {
+		t.Fatalf("cannot parse deprecated --user-name: %v", err)
+	} else if c.UserName != "b" {
+		t.Errorf("parse deprecated --user-name: %v", c.UserName)
+	}
+
+	data, _ := ioutil.ReadFile(stderr.Name())
+	if ans := "warning: --user-name is deprecated: use --user instead\n"; string(data) != ans {
+		t.Errorf("deprecated warning should show once: %#v", string(data))
+	}
+
+	parser.Reset()
+	if err := parser.Parse("--user-name", "b"); err != nil {
+		t.Fatalf("cannot parse deprecated --user-name after reset: %v", err)
+	} else if data, _ := ioutil.ReadFile(stderr.Name()); strings.Count(string(data), "warning:") != 2 {
+		t.Errorf("deprecated warning should show again after reset: %#v", string(data))
+	}
+
+	parser.Version = "v2.0.1"
+	if err := parser.Parse("--user-name", "c"); err == nil {
+		t.Fatalf("expect removed --user-name failure")
+	} else if c.UserName != "b" {
+		t.Errorf("removed --user-name should not set: %v", c.UserName)
+	}
+}
+
+func ExampleSimpleVersion() {
+	argparse.ExitWhenCallback = false
+
+	c := Simple{}
+	parser := argparse.MustNew(&c)
+	parser.Version = "v1.2.3"
+	// the --verbose of the program never changes the version message
+	parser.Parse("--verbose", "--version")
+	// Output:
+	// simple (v1.2.3)
+}
+
+func TestSimpleVersion(t *testing.T) {
+	defer func() { argparse.BuildVersion, argparse.BuildCommit = "", "" }()
+	argparse.BuildVersion, argparse.BuildCommit = "v2.0.0", "abcdef"
+
+	c := Simple{}
+	parser := argparse.MustNew(&c)
+	info := parser.VersionInfo()
+	if info.Name != "simple" || info.Version != "v2.0.0" || info.Commit != "abcdef" {
+		t.Fatalf("version from ldflags: %#v", info)
+	}
+
+	if msg := info.Verbose(); !strings.HasPrefix(msg, "simple (v2.0.0)\ncommit:     abcdef\n") {
+		t.Errorf("verbose version: %#v", msg)
+	}
+
+	data := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(info.JSON()), &data); err != nil {
+		t.Fatalf("invalid JSON version %v: %v", info.JSON(), err)
+	} else if data["version"] != "v2.0.0" || data["commit"] != "abcdef" {
+		t.Errorf("JSON version: %v", info.JSON())
+	}
+
+	parser.Version = "v1.2.3"
+	if info := parser.VersionInfo(); info.Version != "v1.2.3" {
+		t.Errorf("version from parser should override: %#v", info)
+	}
+
+	stdout, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(stdout.Name())
+	defer func(f *os.File) { os.Stdout = f }(os.Stdout)
+	os.Stdout = stdout
+	argparse.ExitWhenCallback = false
+
+	parser.VersionFormat = argparse.VERSION_JSON
+	if err := parser.Parse("--version"); err != nil {
+		t.Fatalf("cannot parse --version as JSON: %v", err)
+	}
+
+	data = map[string]interface{}{}
+	if raw, _ := ioutil.ReadFile(stdout.Name()); json.Unmarshal(raw, &data) != nil {
+		t.Errorf("invalid JSON version: %#v", string(raw))
+	} else if data["name"] != "simple" || data["version"] != "v1.2.3" {
+		t.Errorf("JSON version: %v", data)
+	}
+}
+
+// the structure embeds argparse.Version and provides its own version
+type Versioned struct {
+	argparse.Version
+}
+
+func (Versioned) VersionString() string {
+	return "v3.0.0"
+}
+
+func TestSimpleVersionFormat(t *testing.T) {
+	stdout, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(stdout.Name())
+	defer func(f *os.File) { os.Stdout = f }(os.Stdout)
+	os.Stdout = stdout
+	argparse.ExitWhenCallback = false
+
+	c := struct {
+		argparse.Version
+		argparse.VersionFormat
+	}{}
+	parser := argparse.MustNew(&c)
+	parser.Name, parser.Version = "tool", "v1.2.3"
+
+	if err := parser.Parse("--version-format", "verbose"); err != nil {
+		t.Fatalf("cannot parse --version-format verbose: %v", err)
+	} else if data, _ := ioutil.ReadFile(stdout.Name()); !strings.HasPrefix(string(data), "tool (v1.2.3)\n") ||
+		!strings.Contains(string(data), "\ngo version: ") {
+		t.Errorf("verbose version: %#v", string(data))
+	}
+
+	stdout.Truncate(0)
+	stdout.Seek(0, 0)
+	data := map[string]interface{}{}
+	if err := parser.Parse("--version-format=json"); err != nil {
+		t.Fatalf("cannot parse --version-format=json: %v", err)
+	} else if raw, _ := ioutil.ReadFile(stdout.Name()); json.Unmarshal(raw, &data) != nil || data["version"] != "v1.2.3" {
+		t.Errorf("JSON version: %#v", string(raw))
+	}
+
+	if err := parser.Parse("--version-format=xml"); err == nil {
+		t.Fatalf("expect --version-format=xml failure")
+	}
+}
+
+func TestSimpleVersioner(t *testing.T) {
+	c := Versioned{}
+	if parser := argparse.MustNew(&c); parser.Version != "v3.0.0" {
+		t.Errorf("version from the structure: %#v", parser.Version)
+	}
+}
+
+func TestSimpleSecret(t *testing.T) {
+	file, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("from-file\nnext-line\n")
+	file.Seek(0, 0)
+
+	defer func(f *os.File) { argparse.Stdin = f }(argparse.Stdin)
+	argparse.Stdin = file
+
+	defer os.Unsetenv("ARGPARSE_PASSWORD")
+	os.Setenv("ARGPARSE_PASSWORD", "from-env")
+
+	c := struct {
+		Password string          `args:"password" default:"env:ARGPARSE_PASSWORD"`
+		Token    argparse.Secret `short:"t"`
+	}{}
+	parser := argparse.MustNew(&c)
+	if c.Password != "from-env" {
+		t.Errorf("default secret from env: %#v", c.Password)
+	}
+
+	if line := parser.HelpModel(nil, false).Sections[0].Entries[0].Line; !strings.HasSuffix(line, "(default: ******)") || strings.Contains(line, "from-env") {
+		t.Errorf("default secret should be redacted: %#v", line)
+	}
+
+	if err := parser.Parse("--password=pass:explicit", "-t", "token"); err != nil {
+		t.Fatalf("cannot parse secret: %v", err)
+	} else if c.Password != "explicit" || string(c.Token) != "token" {
+		t.Errorf("parse secret: %#v %#v", c.Password, string(c.Token))
+	} else if fmt.Sprint(c.Token) != "******" || fmt.Sprintf("%#v", c.Token) != `argparse.Secret("******")` {
+		t.Errorf("secret should be redacted: %v %#v", c.Token, c.Token)
+	}
+
+	if err := parser.Parse("--token", "stdin"); err != nil {
+		t.Fatalf("cannot parse secret from stdin: %v", err)
+	} else if string(c.Token) != "from-file" {
+		t.Errorf("parse secret from stdin: %#v", string(c.Token))
+	}
+
+	if err := parser.Parse("--password", "file:"+file.Name()); err != nil {
+		t.Fatalf("cannot parse secret from file: %v", err)
+	} else if c.Password != "from-file" {
+		t.Errorf("parse secret from file: %#v", c.Password)
+	}
+
+	if err := parser.Parse("--password", "env:ARGPARSE_UNKNOWN"); err == nil {
+		t.Errorf("expect unset environment variable failure")
+	}
+
+	if err := parser.Parse("--password"); err == nil {
+		t.Errorf("expect prompt on non-tty failure")
+	}
+
+	deploy := struct {
+		Password string `args:"password"`
+		Deploy   *struct {
+			Force bool
+		}
+	}{}
+	if err := argparse.MustNew(&deploy).Parse("--password", "deploy"); err == nil || deploy.Password == "deploy" {
+		t.Errorf("the sub-command should not be the secret: %v %#v", err, deploy.Password)
+	}
+
+	invalid := struct {
+		Count int `args:"password"`
+	}{}
+	if _, err := argparse.New(&invalid); err == nil {
+		t.Errorf("expect non-string secret failure")
+	}
+}
+
+func TestSimpleReset(t *testing.T) {
+	c := Simple{Count: 123, Name: "simple"}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("-VV", "--count", "1", "x", "y"); err != nil {
+		t.Fatalf("cannot parse: %v", err)
+	}
+
+	c.CustomizedIgnore, c.ignore = true, true
+	parser.Reset()
+	if c.Verbose != 0 || c.Count != 123 || c.Name != "simple" || c.Path != nil {
+		t.Errorf("reset should restore the default: %#v", c)
+	} else if !c.CustomizedIgnore || !c.ignore {
+		t.Errorf("reset should never touch the ignored field: %#v", c)
+	}
+
+	parser.AutoReset = true
+	for _, args := range [][]string{{"-V", "a", "b"}, {"-V", "c"}} {
+		if err := parser.Parse(args...); err != nil {
+			t.Fatalf("cannot parse %v: %v", args, err)
+		}
+	}
+
+	if c.Verbose != 1 || c.Count != 123 || !reflect.DeepEqual(*c.Path, []string{"c"}) {
+		t.Errorf("parse with auto-reset: %#v %#v", c, *c.Path)
+	}
+}
+
+func TestSimpleResponse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Mkdir(filepath.Join(dir, "nested"), 0755)
+	for name, data := range map[string]string{
+		"args.txt":        "# the options\n--count 3 -V\n--user-name \"a b\"  # the user\n@nested/more.txt\n",
+		"nested/more.txt": "x 'y z'\n",
+		"loop.txt":        "@loop.txt\n",
+		"bad.txt":         "-V\n--user-name \"oops\n",
+		"missing.txt":     "-V\n@gone.txt\n",
+	} {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644)
+	}
+
+	c := Simple{}
+	parser := argparse.MustNew(&c)
+	parser.AutoReset = true
+	if err := parser.Parse("@" + filepath.Join(dir, "args.txt")); err != nil {
+		t.Fatalf("cannot parse @ as the argument: %v", err)
+	} else if !reflect.DeepEqual(*c.Path, []string{"@" + filepath.Join(dir, "args.txt")}) {
+		t.Errorf("response file should not be expanded when disabled: %#v", *c.Path)
+	}
+
+	parser.ResponsePrefix = "@"
+	if err := parser.Parse("@"+filepath.Join(dir, "args.txt"), "w"); err != nil {
+		t.Fatalf("cannot parse response file: %v", err)
+	} else if c.Count != 3 || c.Verbose != 1 || c.Name != "a b" || !reflect.DeepEqual(*c.Path, []string{"x", "y z", "w"}) {
+		t.Errorf("parse response file: %#v %#v", c, *c.Path)
+	}
+
+	if err := parser.Parse("@" + filepath.Join(dir, "loop.txt")); err == nil || !strings.Contains(err.Error(), "too many nested") {
+		t.Errorf("expect recursion limit failure: %v", err)
+	}
+
+	bad := filepath.Join(dir, "bad.txt")
+	if err := parser.Parse("@" + bad); err == nil || err.Error() != "response file "+bad+":2: unterminated quote \" at column 13" {
+		t.Errorf("expect the error with file and line: %v", err)
+	}
+
+	missing := filepath.Join(dir, "missing.txt")
+	if err := parser.Parse("@" + missing); err == nil || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expect the not exist failure: %v", err)
+	} else if !strings.HasPrefix(err.Error(), "response file "+missing+":2: cannot read response file ") {
+		t.Errorf("expect the nested error with file and line: %v", err)
+	}
+}
+
+func TestSimpleArgs(t *testing.T) {
+	c := Simple{Count: 3, Name: "simple"}
+	parser := argparse.MustNew(&c)
+	if args, err := parser.Args(); err != nil || len(args) != 0 {
+		t.Errorf("the default should be serialized as empty: %#v %v", args, err)
+	}
+
+	path := []string{"-x", "y z"}
+	c.Switch, c.Count, c.Name, c.Cases, c.Verbose, c.Force, c.Debug, c.Path = true, 7, "", "foo", 2, true, true, &path
+	args, err := parser.Args()
+	if err != nil {
+		t.Fatalf("cannot serialize: %v", err)
+	} else if ans := []string{"--toggle", "--count=7", "--user-name=", "--cases=foo", "--verbose", "--verbose", "--force", "--debug", "--", "-x", "y z"}; !reflect.DeepEqual(args, ans) {
+		t.Errorf("serialize: %#v", args)
+	}
+
+	d := Simple{Count: 3, Name: "simple"}
+	if err := argparse.MustNew(&d).ParseString(argparse.JoinArgs(args)); err != nil {
+		t.Fatalf("cannot parse %v: %v", argparse.JoinArgs(args), err)
+	} else if !reflect.DeepEqual(c, d) {
+		t.Errorf("round trip: %#v %#v", c, d)
+	}
+
+	type Options struct {
+		Color  bool
+		Quiet  bool `action:"set"`
+		Strict bool `action:"set" negate:"false"`
+		Level  *int `args:"option"`
+		Token  argparse.Secret
+	}
+
+	o := Options{Color: true, Quiet: true, Strict: true}
+	parser = argparse.MustNew(&o)
+	level := 0
+	o.Color, o.Quiet, o.Strict, o.Level, o.Token = false, false, false, &level, "s3cret 'x"
+	if args, err = parser.Args(); err != nil {
+		t.Fatalf("cannot serialize: %v", err)
+	} else if ans := []string{"--color", "--no-quiet", "--strict=false", "--level=0", "--token=-"}; !reflect.DeepEqual(args, ans) {
+		t.Errorf("the secret should be prompted instead of serialized: %#v", args)
+	}
+
+	// the secret is prompted on the tty, round trip without it
+	p := Options{Color: true, Quiet: true, Strict: true, Token: o.Token}
+	if err := argparse.MustNew(&p).Parse(args[:len(args)-1]...); err != nil {
+		t.Fatalf("cannot parse %v: %v", args, err)
+	} else if !reflect.DeepEqual(o, p) {
+		t.Errorf("round trip: %#v %#v", o, p)
+	}
+
+	// the secret is passed by the source, round trip without the tty
+	parser.SecretSource = func(name string) string { return "env:SIMPLE_" + strings.ToUpper(name) }
+	if args, err = parser.Args(); err != nil || args[len(args)-1] != "--token=env:SIMPLE_TOKEN" {
+		t.Fatalf("the secret should be passed by the source: %#v %v", args, err)
+	}
+
+	os.Setenv("SIMPLE_TOKEN", string(o.Token))
+	defer os.Unsetenv("SIMPLE_TOKEN")
+	p = Options{Color: true, Quiet: true, Strict: true}
+	if err := argparse.MustNew(&p).Parse(args...); err != nil {
+		t.Fatalf("cannot parse %v: %v", args, err)
+	} else if !reflect.DeepEqual(o, p) {
+		t.Errorf("round trip: %#v %#v", o, p)
+	}
+
+	o.Token = ""
+	if args, err = parser.Args(); err != nil || args[len(args)-1] != "--level=0" {
+		t.Errorf("the empty secret should be skipped: %#v %v", args, err)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cmj0121/argparse"
+)
+
+type Exec struct {
+	argparse.Help
+
+	Env []string `short:"e" help:"set the environment variable"`
+	// capture all the remaining tokens and pass to the child process
+	Command []string `args:"remainder" help:"the command to execute"`
+}
+
+type Wrapper struct {
+	argparse.Help
+	argparse.DumpConfig
+
+	Debug bool `short:"d" help:"show the debug message"`
+
+	*Exec `help:"execute the command"`
+}
+
+func main() {
+	c := Wrapper{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Run(); err == nil {
+		data, _ := json.MarshalIndent(c, "", "    ")
+		fmt.Println(string(data))
+	}
+}
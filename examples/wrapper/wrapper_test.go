@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cmj0121/argparse"
+)
+
+func ExampleWrapper() {
+	argparse.Stderr = os.Stdout
+	argparse.ExitWhenCallback = false
+
+	c := Exec{}
+	parser := argparse.MustNew(&c)
+	parser.Parse("-h")
+	// Output:
+	// usage: exec [OPTIONS] [--] [COMMAND...]
+	//
+	// option:
+	//          -h, --help                  show this message
+	//      -e STR, --env STR               set the environment variable
+	//
+	// argument:
+	//     COMMAND                          the command to execute
+}
+
+func TestWrapperColor(t *testing.T) {
+	defer os.Unsetenv("CLICOLOR_FORCE")
+	defer os.Unsetenv("NO_COLOR")
+
+	c := Exec{}
+	parser := argparse.MustNew(&c)
+	plain, _ := parser.HelpModel(fmt.Errorf("oops"), false).Render("")
+
+	os.Setenv("CLICOLOR_FORCE", "1")
+	styled, err := parser.HelpModel(fmt.Errorf("oops"), false).Render("")
+	if err != nil {
+		t.Fatalf("cannot render styled help: %v", err)
+	}
+
+	for _, ans := range []string{"\x1b[1;31merror:\x1b[0m oops", "\x1b[1moption\x1b[0m:", "\x1b[36m--env\x1b[0m \x1b[33mSTR\x1b[0m"} {
+		if !strings.Contains(styled, ans) {
+			t.Errorf("styled help should contain %#v: %#v", ans, styled)
+		}
+	}
+
+	if stripped := regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(styled, ""); stripped != plain {
+		t.Errorf("styled help should be the same as plain text:\n%v\n%v", stripped, plain)
+	}
+
+	os.Setenv("NO_COLOR", "1")
+	if msg, _ := parser.HelpModel(fmt.Errorf("oops"), false).Render(""); msg != plain {
+		t.Errorf("NO_COLOR should disable the style: %#v", msg)
+	}
+
+	parser.Theme = nil
+	os.Unsetenv("NO_COLOR")
+	if msg, _ := parser.HelpModel(fmt.Errorf("oops"), false).Render(""); msg != plain {
+		t.Errorf("nil theme should disable the style: %#v", msg)
+	}
+}
+
+func TestWrapper(t *testing.T) {
+	c := Wrapper{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("-d", "exec", "-e", "A=1", "--", "docker", "run", "--rm", "-it"); err != nil {
+		t.Fatalf("cannot parse exec -- docker run: %v", err)
+	} else {
+		if ans := []string{"docker", "run", "--rm", "-it"}; !reflect.DeepEqual(c.Exec.Command, ans) {
+			t.Errorf("parse exec -- docker run --rm -it: %#v", c.Exec.Command)
+		}
+		if ans := []string{"A=1"}; !reflect.DeepEqual(c.Exec.Env, ans) {
+			t.Errorf("parse exec -e A=1: %#v", c.Exec.Env)
+		}
+	}
+
+	c = Wrapper{}
+	parser = argparse.MustNew(&c)
+	if err := parser.Parse("exec", "ls", "-al", "--", "/"); err != nil {
+		t.Fatalf("cannot parse exec ls -al -- /: %v", err)
+	} else if ans := []string{"ls", "-al", "--", "/"}; !reflect.DeepEqual(c.Exec.Command, ans) {
+		t.Errorf("parse exec ls -al -- /: %#v", c.Exec.Command)
+	}
+}
+
+func TestWrapperKnown(t *testing.T) {
+	c := Wrapper{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("--unknown", "-d"); err == nil {
+		t.Fatalf("expect --unknown failure")
+	}
+
+	c = Wrapper{}
+	parser = argparse.MustNew(&c)
+	if unknown, err := parser.ParseKnown("--unknown", "-dx", "value", "exec", "--tty", "ls"); err != nil {
+		t.Fatalf("cannot parse known: %v", err)
+	} else {
+		if ans := []string{"--unknown", "-x", "value", "--tty"}; !reflect.DeepEqual(unknown, ans) {
+			t.Errorf("parse known, unknown: %#v", unknown)
+		}
+		if ans := []string{"ls"}; !reflect.DeepEqual(c.Exec.Command, ans) {
+			t.Errorf("parse known, command: %#v", c.Exec.Command)
+		}
+		if !c.Debug {
+			t.Errorf("parse known -d: %v", c.Debug)
+		}
+	}
+}
+
+func TestWrapperParseString(t *testing.T) {
+	c := Wrapper{}
+	parser := argparse.MustNew(&c)
+	parser.LookupEnv = func(name string) (string, bool) { return "/opt/" + name, true }
+	if err := parser.ParseString(`-d exec -e "HOME=$HOME" -- sh -c 'echo $HOME'`); err != nil {
+		t.Fatalf("cannot parse string: %v", err)
+	} else if ans := []string{"HOME=/opt/HOME"}; !reflect.DeepEqual(c.Exec.Env, ans) {
+		t.Errorf("parse string with variable: %#v", c.Exec.Env)
+	} else if ans := []string{"sh", "-c", "echo $HOME"}; !reflect.DeepEqual(c.Exec.Command, ans) {
+		t.Errorf("parse string with single quote: %#v", c.Exec.Command)
+	}
+
+	if err := parser.ParseString(`exec "ls`); err == nil || err.Error() != "unterminated quote \" at column 6" {
+		t.Errorf("expect unterminated quote failure: %v", err)
+	}
+}
+
+func TestWrapperArgs(t *testing.T) {
+	c := Wrapper{Debug: true, Exec: &Exec{Env: []string{"A=1"}, Command: []string{"sh", "-c", "echo $HOME"}}}
+	parser := argparse.MustNew(&Wrapper{})
+	parser.Value.Elem().Set(reflect.ValueOf(c))
+
+	args, err := parser.Args()
+	if err != nil {
+		t.Fatalf("cannot serialize: %v", err)
+	} else if ans := []string{"--debug", "exec", "--env=A=1", "--", "sh", "-c", "echo $HOME"}; !reflect.DeepEqual(args, ans) {
+		t.Errorf("serialize: %#v", args)
+	} else if line := argparse.JoinArgs(args); line != `--debug exec --env=A=1 -- sh -c 'echo $HOME'` {
+		t.Errorf("join: %v", line)
+	}
+
+	d := Wrapper{}
+	parser = argparse.MustNew(&d)
+	parser.LookupEnv = os.LookupEnv
+	if err := parser.ParseString(argparse.JoinArgs(args)); err != nil {
+		t.Fatalf("cannot parse %v: %v", args, err)
+	} else if !reflect.DeepEqual(c, d) {
+		t.Errorf("round trip: %#v %#v", c.Exec, d.Exec)
+	}
+}
+
+func ExampleWrapperDumpConfig() {
+	argparse.ExitWhenCallback = false
+
+	for _, format := range []string{"yaml", "json", "env", "args"} {
+		c := Wrapper{}
+		parser := argparse.MustNew(&c)
+		parser.Parse("--dump-config", format, "exec", "-e", "A=1", "--", "sh", "-c", "echo $HOME")
+	}
+	// Output:
+	// # the effective configuration of wrapper
+	// debug: false  # default
+	// exec:
+	//   env: ["A=1"]  # argument
+	//   command: ["sh","-c","echo $HOME"]  # argument
+	// {
+	//     "debug": {"value": false, "source": "default"},
+	//     "exec": {
+	//         "env": {"value": ["A=1"], "source": "argument"},
+	//         "command": {"value": ["sh","-c","echo $HOME"], "source": "argument"}
+	//     }
+	// }
+	// # WRAPPER_DEBUG: default
+	// WRAPPER_DEBUG=false
+	// # WRAPPER_EXEC_ENV: argument
+	// WRAPPER_EXEC_ENV=A=1
+	// # WRAPPER_EXEC_COMMAND: argument
+	// WRAPPER_EXEC_COMMAND=sh -c 'echo $HOME'
+	// # the effective configuration of wrapper, the default is omitted
+	// exec
+	// --env=A=1
+	// -- sh -c 'echo $HOME'
+}
+
+func TestWrapperDumpConfig(t *testing.T) {
+	c := Wrapper{}
+	parser := argparse.MustNew(&c)
+	if err := parser.Parse("-d", "exec", "-e", "A=1 B", "--", "ls", "-al"); err != nil {
+		t.Fatalf("cannot parse: %v", err)
+	}
+
+	dump, err := parser.DumpConfig("args")
+	if err != nil {
+		t.Fatalf("cannot dump: %v", err)
+	}
+
+	file, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(dump)
+	file.Close()
+
+	d := Wrapper{}
+	parser = argparse.MustNew(&d)
+	parser.ResponsePrefix = "@"
+	if err := parser.Parse("@" + file.Name()); err != nil {
+		t.Fatalf("cannot feed back %v: %v", dump, err)
+	} else if !reflect.DeepEqual(c, d) {
+		t.Errorf("feed back: %#v %#v", c.Exec, d.Exec)
+	}
+
+	if env, err := argparse.MustNew(&c).DumpConfig("env"); err != nil {
+		t.Fatalf("cannot dump env: %v", err)
+	} else if vars := readEnvFile(env); !reflect.DeepEqual(vars, map[string]string{
+		"WRAPPER_DEBUG":        "true",
+		"WRAPPER_EXEC_ENV":     "'A=1 B'",
+		"WRAPPER_EXEC_COMMAND": "ls -al",
+	}) {
+		t.Errorf("parse back the env file %#v: %#v", env, vars)
+	} else if env, err := argparse.SplitArgs(vars["WRAPPER_EXEC_ENV"], nil); err != nil || !reflect.DeepEqual(env, c.Exec.Env) {
+		t.Errorf("split the env list: %#v %v", env, err)
+	}
+
+	if _, err := parser.DumpConfig("xml"); err == nil || err.Error() != "unknown format: xml" {
+		t.Errorf("expect unknown format failure: %v", err)
+	}
+
+	s := struct {
+		Token argparse.Secret
+		Level int `default:"1"`
+		Name  *string
+	}{}
+	parser = argparse.MustNew(&s)
+	if err := parser.Parse("--token", "pass:s3cret", "--level=1", "x"); err != nil {
+		t.Fatalf("cannot parse: %v", err)
+	}
+
+	config, err := parser.Config()
+	if err != nil {
+		t.Fatalf("cannot get the config: %v", err)
+	}
+
+	ans := []argparse.ConfigEntry{
+		{Name: "token", Value: "******", Source: "argument", Secret: true, Args: []string{"--token=-"}},
+		{Name: "level", Value: int64(1), Source: "argument"},
+		{Name: "name", Value: "x", Source: "argument"},
+	}
+	if !reflect.DeepEqual(config.Entries, ans) {
+		t.Errorf("config: %#v", config.Entries)
+	}
+
+	for _, format := range []string{"args", "env", "json", "yaml"} {
+		if dump, err := config.Format(format); err != nil {
+			t.Errorf("cannot dump %v: %v", format, err)
+		} else if strings.Contains(dump, "s3cret") {
+			t.Errorf("dump %v should redact the secret: %v", format, dump)
+		}
+	}
+
+	if dump, _ := config.Format("args"); !strings.Contains(dump, "# the secret passed as - is prompted on the tty") {
+		t.Errorf("the header should note the prompted secret: %v", dump)
+	}
+
+	parser.SecretSource = func(name string) string { return "file:/run/secrets/" + name }
+	if dump, err := parser.DumpConfig("args"); err != nil {
+		t.Fatalf("cannot dump args: %v", err)
+	} else if !strings.Contains(dump, "\n--token=file:/run/secrets/token\n") || strings.Contains(dump, "prompted") {
+		t.Errorf("the secret should be passed by the source: %v", dump)
+	}
+}
+
+// read the env file like systemd EnvironmentFile: the comment line is skipped, the value is raw or
+// double quoted with the backslash escape
+func readEnvFile(data string) (vars map[string]string) {
+	vars = map[string]string{}
+	for _, line := range strings.Split(data, "\n") {
+		pos := strings.Index(line, "=")
+		if strings.HasPrefix(line, "#") || pos < 0 {
+			continue
+		}
+
+		value := line[pos+1:]
+		if strings.HasPrefix(value, `"`) {
+			value = strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(value[1 : len(value)-1])
+		}
+		vars[line[:pos]] = value
+	}
+	return
+}
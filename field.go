@@ -19,6 +19,7 @@ const (
 	OPTION FieldType = iota
 	ARGUMENT
 	SUBCOMMAND
+	REMAINDER
 )
 
 func (ftyp FieldType) String() (str string) {
@@ -26,6 +27,7 @@ func (ftyp FieldType) String() (str string) {
 		"OPTION",
 		"ARGUMENT",
 		"SUB-COMMAND",
+		"REMAINDER",
 	}
 	str = ftyps[ftyp]
 	return
@@ -55,6 +57,29 @@ type Field struct {
 	Callback     string
 	DefaultValue interface{}
 	Choices      []string
+	Action       string
+	// disable the --no-NAME for the boolean option
+	NoNegate bool
+
+	// the alias names of the field, the alias with one rune is the shortcut
+	Aliases       []string
+	HiddenAliases []string
+
+	// the deprecated message, and raise error since the Removed version
+	Deprecated string
+	Removed    string
+	warned     bool
+
+	// not shown in the help message
+	Hidden bool
+	// the group shown in the help message
+	Group string
+	// the mutually exclusive set, only one option of the set can be passed
+	Exclusive string
+	// the secret option, never shown the value
+	Secret bool
+	// the snapshot of the value when New, used to skip the default and restored by Reset
+	initial reflect.Value
 }
 
 func NewField(value reflect.Value, sfield reflect.StructField, ftyp FieldType) (field *Field, err error) {
@@ -73,7 +98,7 @@ func NewField(value reflect.Value, sfield reflect.StructField, ftyp FieldType) (
 
 	// customized pre-process by field type
 	switch ftyp {
-	case ARGUMENT:
+	case ARGUMENT, REMAINDER:
 		// set the display as the upper-case
 		field.Name = strings.ToUpper(field.Name)
 	case SUBCOMMAND:
@@ -87,6 +112,8 @@ func NewField(value reflect.Value, sfield reflect.StructField, ftyp FieldType) (
 			// cannot set the sub-command
 			return
 		}
+		// inherit the theme of the parent unless set
+		field.Subcommand.Theme = nil
 
 		if field.StructTag.Get(TAG_NAME) != "" {
 			field.Subcommand.Name = strings.ToLower(field.StructTag.Get(TAG_NAME))
@@ -109,12 +136,82 @@ func NewField(value reflect.Value, sfield reflect.StructField, ftyp FieldType) (
 		}
 	}
 
+	if field.Aliases, err = field.parseAlias(TAG_ALIAS); err != nil {
+		// invalid alias
+		return
+	}
+	if field.HiddenAliases, err = field.parseAlias(TAG_HIDDEN_ALIAS); err != nil {
+		// invalid alias
+		return
+	}
+
+	if deprecated := field.StructTag.Get(TAG_DEPRECATED); deprecated != "" {
+		// set the deprecated message
+		field.Deprecated = strings.TrimSpace(deprecated)
+	}
+	if removed := field.StructTag.Get(TAG_REMOVED); removed != "" {
+		if field.Deprecated == "" {
+			err = fmt.Errorf("%v should set with %v", TAG_REMOVED, TAG_DEPRECATED)
+			return
+		}
+		field.Removed = strings.TrimSpace(removed)
+	}
+
+	if hidden := field.StructTag.Get(TAG_HIDDEN); hidden != "" {
+		if field.Hidden, err = ParseBool(hidden); err != nil {
+			err = fmt.Errorf("invalid %v: %v", TAG_HIDDEN, err)
+			return
+		}
+	}
+
+	if group := field.StructTag.Get(TAG_GROUP); group != "" {
+		switch field.FieldType {
+		case OPTION, SUBCOMMAND:
+			field.Group = strings.TrimSpace(group)
+		default:
+			err = fmt.Errorf("%v only allowed on option and sub-command", TAG_GROUP)
+			return
+		}
+	}
+
+	if exclusive := field.StructTag.Get(TAG_EXCLUSIVE); exclusive != "" {
+		if field.FieldType != OPTION {
+			err = fmt.Errorf("%v only allowed on option", TAG_EXCLUSIVE)
+			return
+		}
+		field.Exclusive = strings.TrimSpace(exclusive)
+	}
+
 	if help := field.StructTag.Get(TAG_HELP); help != "" {
 		// set the help message
 		field.Help = help
 	}
 
-	if defaultV := field.StructTag.Get(TAG_DEFAULT_KEY); defaultV != "" {
+	if typ := field.Type; isSecretField(sfield) {
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		if field.FieldType != OPTION || typ.Kind() != reflect.String {
+			err = fmt.Errorf("secret should be the string option: %v", field.Type)
+			return
+		}
+		field.Secret = true
+	}
+
+	if defaultV := field.StructTag.Get(TAG_DEFAULT_KEY); defaultV != "" && field.Secret {
+		log.Info("try set default secret from %v", field.Name)
+		if defaultV == "-" {
+			err = fmt.Errorf("cannot prompt the default secret")
+			return
+		}
+
+		if err = field.setSecretSource(defaultV); err != nil {
+			// the default secret may not exist, like unset environment variable
+			log.Warn("cannot set default secret %v: %v", field.Name, err)
+			err = nil
+		}
+	} else if defaultV != "" {
 		log.Info("try set default value: %v", defaultV)
 		if _, err = field.setValue(field.Value, defaultV); err != nil {
 			log.Warn("cannot set default value %#v: %v", defaultV, err)
@@ -122,6 +219,22 @@ func NewField(value reflect.Value, sfield reflect.StructField, ftyp FieldType) (
 		}
 	}
 
+	if action := field.StructTag.Get(TAG_ACTION); action != "" {
+		if err = field.setAction(strings.TrimSpace(action)); err != nil {
+			// invalid action
+			return
+		}
+	}
+
+	if negate := field.StructTag.Get(TAG_NEGATE); negate != "" {
+		var ok bool
+		if ok, err = ParseBool(negate); err != nil {
+			err = fmt.Errorf("invalid %v: %v", TAG_NEGATE, err)
+			return
+		}
+		field.NoNegate = !ok
+	}
+
 	if callback := field.StructTag.Get(TAG_CALLBACK); callback != "" {
 		// set the callback name
 		field.Callback = callback
@@ -154,7 +267,7 @@ func NewField(value reflect.Value, sfield reflect.StructField, ftyp FieldType) (
 			field.DefaultValue = field.Value.Interface()
 		}
 
-		log.Debug("set default: %#v", field.DefaultValue)
+		log.Debug("set default: %#v", field.redact(field.DefaultValue))
 	}
 
 	typ := field.Type
@@ -192,6 +305,192 @@ func (field *Field) String() (str string) {
 	return
 }
 
+// parse the alias names from the tag, separated by TAG_ALIAS_SEP
+func (field *Field) parseAlias(key string) (aliases []string, err error) {
+	tag := field.StructTag.Get(key)
+	if tag == "" {
+		// no alias
+		return
+	}
+
+	switch field.FieldType {
+	case OPTION, SUBCOMMAND:
+	default:
+		err = fmt.Errorf("%v only allowed on option and sub-command", key)
+		return
+	}
+
+	for _, alias := range strings.Split(tag, TAG_ALIAS_SEP) {
+		alias = strings.TrimSpace(alias)
+		if len([]rune(alias)) > 1 || field.FieldType == SUBCOMMAND {
+			// only the long name treated as the lowercase
+			alias = strings.ToLower(alias)
+		}
+
+		switch {
+		case alias == "":
+			err = fmt.Errorf("empty %v: %#v", key, tag)
+			return
+		case alias[0] == '-' || strings.ContainsAny(alias, " \t="):
+			err = fmt.Errorf("invalid %v: %#v", key, alias)
+			return
+		}
+
+		aliases = append(aliases, alias)
+	}
+	return
+}
+
+// the name is the hidden alias of the field
+func (field *Field) isHiddenAlias(name string) (ok bool) {
+	for _, alias := range field.HiddenAliases {
+		if ok = alias == name; ok {
+			return
+		}
+	}
+	return
+}
+
+// the display name of the field used in the message
+func (field *Field) DisplayName() (name string) {
+	switch name = field.Name; field.FieldType {
+	case OPTION:
+		name = "--" + field.Name
+	}
+	return
+}
+
+// show the warning when use the deprecated field, and raise error since the removed version
+func (field *Field) checkDeprecated(parser *ArgParse) (err error) {
+	if field.Deprecated == "" {
+		// not deprecated
+		return
+	}
+
+	if field.Removed != "" && parser.Version != "" && compareVersion(parser.Version, field.Removed) >= 0 {
+		err = fmt.Errorf("%v is removed since %v: %v", field.DisplayName(), field.Removed, field.Deprecated)
+		return
+	}
+
+	if !field.warned {
+		// only show the warning once
+		field.warned = true
+		log.Warn("use deprecated %v", field.DisplayName())
+		warning := themeFor(parser.Theme, Stderr).Style(parser.Theme.warning(), "warning:")
+		Stderr.WriteString(fmt.Sprintf("%v %v is deprecated: %v\n", warning, field.DisplayName(), field.Deprecated))
+	}
+	return
+}
+
+// check the option is not passed with another one in the same mutually exclusive set
+func (field *Field) checkExclusive(parser *ArgParse) (err error) {
+	if field.Exclusive == "" {
+		// not exclusive
+		return
+	}
+
+	switch other, ok := parser.exclusive[field.Exclusive]; {
+	case !ok:
+		parser.exclusive[field.Exclusive] = field
+	case other != field:
+		err = fmt.Errorf("%v cannot be used with %v", field.DisplayName(), other.DisplayName())
+	}
+	return
+}
+
+// set the action of the option, only allowed on the specified type
+func (field *Field) setAction(action string) (err error) {
+	typ := field.Type
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch action {
+	case ACTION_TOGGLE, ACTION_SET:
+		if typ.Kind() != reflect.Bool {
+			err = fmt.Errorf("action %v only allowed on bool: %v", action, field.Type)
+			return
+		}
+	case ACTION_COUNT:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			err = fmt.Errorf("action %v only allowed on integer: %v", action, field.Type)
+			return
+		}
+
+		// the counter does not need the extra variable
+		field.TypeHint = ""
+	default:
+		err = fmt.Errorf("unknown action: %v", action)
+		return
+	}
+
+	if field.FieldType != OPTION {
+		err = fmt.Errorf("action %v only allowed on option", action)
+		return
+	}
+
+	field.Action = action
+	return
+}
+
+// the option can be set as false via --no-NAME
+func (field *Field) Negatable() (ok bool) {
+	ok = field.FieldType == OPTION && field.IsBool() && !field.NoNegate
+	return
+}
+
+// the negative name of the option, like --no-NAME or --no-ALIAS
+func (field *Field) isNegative(name string) (ok bool) {
+	if !field.Negatable() || !strings.HasPrefix(name, "--no-") {
+		return
+	}
+
+	name = name[len("--no-"):]
+	if ok = name == field.Name; ok {
+		return
+	}
+
+	for _, alias := range field.Aliases {
+		if ok = alias == name; ok {
+			return
+		}
+	}
+	return
+}
+
+// the field is the boolean or the pointer of the boolean
+func (field *Field) IsBool() (ok bool) {
+	typ := field.Type
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	ok = typ.Kind() == reflect.Bool
+	return
+}
+
+// check the field is the slice type, which can be set repeatedly
+func (field *Field) IsSlice() (ok bool) {
+	typ := field.Type
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	ok = typ.Kind() == reflect.Slice
+	return
+}
+
+// the display name of the option, include the negative prefix
+func (field *Field) OptionName() (name string) {
+	if name = field.Name; field.Negatable() {
+		name = fmt.Sprintf("[no-]%v", field.Name)
+	}
+	return
+}
+
 func (field *Field) setTypeHint(typ reflect.Type) {
 	switch typ.Kind() {
 	case reflect.Int:
@@ -222,20 +521,44 @@ func (field *Field) setTypeHint(typ reflect.Type) {
 // | margin | pending  | size | margin |      |
 // |        | Shortcut | Name |        | Help |
 func (field *Field) FormatString(margin, pending, size int) (str string) {
-	option := field.Name
+	str = field.FormatWrapString(margin, pending, size, 0)
+	return
+}
+
+// the format string for the field, and wrap the help message within the width with hanging
+// indentation, never wrap when width is 0
+func (field *Field) FormatWrapString(margin, pending, size, width int) (str string) {
+	str = field.formatString(margin, pending, size, width, nil)
+	return
+}
+
+// the format string for the field with the optional theme, the padding is always calculated
+// by the plain text
+func (field *Field) formatString(margin, pending, size, width int, theme *Theme) (str string) {
+	option, styled := field.Name, theme.Style(theme.option(), field.Name)
 
 	switch field.FieldType {
 	case OPTION:
+		name, hint := "--"+field.OptionName(), ""
+		if field.TypeHint != "" {
+			// the styled type hint
+			hint = " " + theme.Style(theme.hint(), field.TypeHint)
+		}
+
 		// --KEY TYPE
-		option = fmt.Sprintf("%*v--%v %v", pending, "", field.Name, field.TypeHint)
+		option = fmt.Sprintf("%*v--%v %v", pending, "", field.OptionName(), field.TypeHint)
 		option = strings.TrimRight(option, " \t\n")
+		styled = fmt.Sprintf("%*v%v%v", pending, "", theme.Style(theme.option(), name), hint)
 
 		// -SHORT TYPE, --KEY TYPE
 		if field.Shortcut != rune(0) {
 			shortcut := fmt.Sprintf("-%v %v", string(field.Shortcut), field.TypeHint)
 			shortcut = fmt.Sprintf("%v, ", strings.TrimSpace(shortcut))
-			shift := len(shortcut) - WidecharSize(shortcut)
-			option = fmt.Sprintf("%*v--%v %v", pending-shift, shortcut, field.Name, field.TypeHint)
+			option = fmt.Sprintf("%v--%v %v", padLeft(shortcut, pending), field.OptionName(), field.TypeHint)
+
+			lead := WidecharSize(padLeft(shortcut, pending)) - WidecharSize(shortcut)
+			short := theme.Style(theme.option(), "-"+string(field.Shortcut))
+			styled = fmt.Sprintf("%*v%v%v, %v %v", lead, "", short, hint, theme.Style(theme.option(), name), theme.Style(theme.hint(), field.TypeHint))
 		}
 	}
 
@@ -245,34 +568,182 @@ func (field *Field) FormatString(margin, pending, size int) (str string) {
 		help = fmt.Sprintf("%v [%v]", help, choices)
 	}
 
+	if len(field.Aliases) > 0 {
+		aliases := []string{}
+		for _, alias := range field.Aliases {
+			switch {
+			case field.FieldType == SUBCOMMAND:
+				aliases = append(aliases, alias)
+			case len([]rune(alias)) == 1:
+				aliases = append(aliases, "-"+alias)
+			case field.Negatable():
+				aliases = append(aliases, "--[no-]"+alias)
+			default:
+				aliases = append(aliases, "--"+alias)
+			}
+		}
+		help = fmt.Sprintf("%v (alias: %v)", help, strings.Join(aliases, ", "))
+	}
+
+	if field.Deprecated != "" {
+		// show the deprecated message
+		help = fmt.Sprintf("%v (deprecated: %v)", help, field.Deprecated)
+	}
+
+	if field.Hidden {
+		// only shown when show all the fields
+		help = fmt.Sprintf("%v (hidden)", help)
+	}
+
 	if field.DefaultValue != nil {
 		// set the default value
 		switch field.FieldType {
 		case ARGUMENT:
-			help = fmt.Sprintf("%v (default: %v)", help, field.DefaultValue)
+			help = fmt.Sprintf("%v (default: %v)", help, field.redact(field.DefaultValue))
 		default:
-			help = fmt.Sprintf("%v (default: %v)", help, field.DefaultValue)
+			help = fmt.Sprintf("%v (default: %v)", help, field.redact(field.DefaultValue))
 		}
 	}
 
-	shift := len(option) - WidecharSize(option)
-	str = fmt.Sprintf("%*v%-*v%*v", margin, "", pending+size-shift, option, margin, strings.TrimSpace(help))
+	indent := margin + pending + size
+	if width > 0 && width-indent < FMT_MIN_WRAP {
+		// too narrow to show the help message
+		width = indent + FMT_MIN_WRAP
+	}
+
+	lines := wrapText(strings.TrimSpace(help), width-indent)
+	if width <= 0 {
+		// never wrap
+		lines = wrapText(strings.TrimSpace(help), 0)
+	}
+
+	if theme != nil && field.DefaultValue != nil {
+		// the styled default value
+		value := fmt.Sprintf("(default: %v)", field.redact(field.DefaultValue))
+		for idx, line := range lines {
+			lines[idx] = strings.Replace(line, value, theme.Style(theme.Default, value), 1)
+		}
+	}
+
+	padding := pending + size - WidecharSize(option)
+	if padding < 0 {
+		padding = 0
+	}
+
+	str = fmt.Sprintf("%*v%v%*v%*v", margin, "", styled, padding, "", margin, lines[0])
 	str = strings.TrimRight(str, " \t\n")
+	for _, line := range lines[1:] {
+		// the hanging indentation
+		line = fmt.Sprintf("%*v%v", indent, "", line)
+		str = fmt.Sprintf("%v\n%v", str, strings.TrimRight(line, " \t\n"))
+	}
 	return
 }
 
 // pre-process the field setting, include new instance
 func (field *Field) SetValue(parser *ArgParse, args ...string) (size int, err error) {
+	if err = field.checkDeprecated(parser); err != nil {
+		return
+	} else if err = field.checkExclusive(parser); err != nil {
+		return
+	}
+
+	if field.Secret {
+		// the secret setter
+		if size, err = field.setSecret(parser, args...); err != nil {
+			return
+		}
+
+		field.trigger(parser)
+		return
+	}
+
 	size = 1
 	// the basic setter
 	if size, err = field.setValue(field.Value, args...); err != nil {
 		return
 	}
 
+	field.trigger(parser)
+	log.Info("set %v as %v (%d)", field.Name, field.Value, size)
+	return
+}
+
+// set the value from the explicit --NAME=VALUE
+func (field *Field) SetExplicitValue(parser *ArgParse, value string) (err error) {
+	if err = field.checkDeprecated(parser); err != nil {
+		return
+	} else if err = field.checkExclusive(parser); err != nil {
+		return
+	}
+
+	switch {
+	case field.Secret:
+		if err = field.setSecretSource(value); err != nil {
+			return
+		}
+		field.trigger(parser)
+		return
+	case field.IsBool():
+		var ok bool
+		if ok, err = ParseBool(value); err != nil {
+			// invalid boolean
+			return
+		}
+
+		if err = field.setBool(ok); err != nil {
+			return
+		} else if !ok {
+			// the explicit false never triggers the callback, like --help=false
+			field.BeenSet = true
+			break
+		}
+		field.trigger(parser)
+	case field.Action == ACTION_COUNT:
+		err = fmt.Errorf("should not pass value: %v", value)
+		return
+	default:
+		var size int
+		if size, err = field.setValue(field.Value, value); err != nil {
+			return
+		} else if size == 0 {
+			err = fmt.Errorf("should not pass value: %v", value)
+			return
+		}
+		field.trigger(parser)
+	}
+
+	log.Info("set %v as %v", field.Name, field.Value)
+	return
+}
+
+// set the negative value via --no-NAME
+func (field *Field) SetNegative(parser *ArgParse) (err error) {
+	if err = field.checkDeprecated(parser); err != nil {
+		return
+	} else if err = field.checkExclusive(parser); err != nil {
+		return
+	}
+
+	if err = field.setBool(false); err != nil {
+		return
+	}
+
+	field.BeenSet = true
+	log.Info("set %v as %v", field.Name, field.Value)
+	return
+}
+
+// execute the callback and mark the field been set
+func (field *Field) trigger(parser *ArgParse) {
 	if fn := GetCallback(parser.Value, field.Callback); fn != nil {
 		log.Debug("try execute %v", field.Callback)
 		// trigger the callback, exit when callback return true
-		if fn(parser) && ExitWhenCallback {
+		switch exit := fn(parser); {
+		case exit && parser.no_exit:
+			log.Info("execute callback %v, and stop parsing", field.Callback)
+			parser.stopped = true
+		case exit && ExitWhenCallback:
 			log.Info("execute callback %v, and exit 0", field.Callback)
 			os.Exit(0)
 		}
@@ -283,19 +754,73 @@ func (field *Field) SetValue(parser *ArgParse, args ...string) (size int, err er
 		// can set repeat
 		field.BeenSet = false
 	}
-	log.Info("set %v as %v (%d)", field.Name, field.Value, size)
+}
+
+// set the boolean value, include the pointer of the boolean
+func (field *Field) setBool(ok bool) (err error) {
+	value := field.Value
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			// nil pointer, new instance
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Bool {
+		err = fmt.Errorf("cannot set boolean on %v", field.Type)
+		return
+	}
+
+	value.SetBool(ok)
+	return
+}
+
+// set all the remaining tokens to the remainder field without parsing
+func (field *Field) SetRemainder(parser *ArgParse, args ...string) (err error) {
+	if err = field.checkDeprecated(parser); err != nil {
+		return
+	}
+
+	for _, arg := range args {
+		if _, err = field.setValue(field.Value, arg); err != nil {
+			// cannot append the token, raise
+			return
+		}
+	}
+
+	field.BeenSet = true
+	log.Info("set remainder %v as %v", field.Name, field.Value)
 	return
 }
 
 // the exactly set the value to the field
 func (field *Field) setValue(value reflect.Value, args ...string) (size int, err error) {
-	log.Debug("try set value %[1]T (%#v)", value.Interface(), args)
+	// the arguments may contain the secret, never logged
+	log.Debug("try set value %T (%d args)", value.Interface(), len(args))
 
 	switch value.Interface().(type) {
 	case bool:
-		// toggle the boolean
-		value.SetBool(!value.Interface().(bool))
+		switch field.Action {
+		case ACTION_SET:
+			// always set as true
+			value.SetBool(true)
+		default:
+			// toggle the boolean
+			value.SetBool(!value.Interface().(bool))
+		}
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		if field.Action == ACTION_COUNT {
+			// increase the counter without the extra variable
+			switch value.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				value.SetInt(value.Int() + 1)
+			default:
+				value.SetUint(value.Uint() + 1)
+			}
+			break
+		}
+
 		// override the integer
 		if len(args) == 0 {
 			err = fmt.Errorf("should pass %v", TYPE_INT)
@@ -311,12 +836,12 @@ func (field *Field) setValue(value reflect.Value, args ...string) (size int, err
 		if len(field.Choices) > 0 {
 			idx := sort.SearchStrings(field.Choices, args[0])
 			if idx == len(field.Choices) || field.Choices[idx] != args[0] {
-				err = fmt.Errorf("%v should choice from %v", args[0], field.Choices)
+				err = fmt.Errorf("%v should choice from %v%v", args[0], field.Choices, suggestMessage(args[0], field.Choices))
 				return
 			}
 		}
 
-		switch field.Value.Kind() {
+		switch value.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			value.SetInt(int64(val))
 		default:
@@ -334,7 +859,7 @@ func (field *Field) setValue(value reflect.Value, args ...string) (size int, err
 		if len(field.Choices) > 0 {
 			idx := sort.SearchStrings(field.Choices, args[0])
 			if idx == len(field.Choices) || field.Choices[idx] != args[0] {
-				err = fmt.Errorf("%v should choice from %v", args[0], field.Choices)
+				err = fmt.Errorf("%v should choice from %v%v", args[0], field.Choices, suggestMessage(args[0], field.Choices))
 				return
 			}
 		}
@@ -435,6 +960,11 @@ func (field *Field) setValue(value reflect.Value, args ...string) (size int, err
 		case reflect.Struct:
 			// execute sub-command
 			if err = field.Subcommand.Parse(args...); err != nil {
+				if field.Subcommand.no_exit {
+					// raise the error to the caller, like the shell
+					return
+				}
+
 				// only show the help message on the sub-command
 				field.Subcommand.HelpMessage(err)
 				os.Exit(1)
@@ -461,7 +991,7 @@ func (field *Field) setValue(value reflect.Value, args ...string) (size int, err
 		case reflect.Slice:
 			elem := reflect.New(value.Type().Elem()).Elem()
 			if size, err = field.setValue(elem, args...); err != nil {
-				err = fmt.Errorf("cannot set %#v: %v", value.Type(), err)
+				err = fmt.Errorf("cannot set %v: %v", value.Type(), err)
 				return
 			}
 
@@ -560,13 +1090,52 @@ func (field *Field) getCIRD(in string) (inet *net.IPNet) {
 	return
 }
 
-// calculate the multiple-char size
-func WidecharSize(widechar string) (siz int) {
-	for _, s := range widechar {
-		siz++
-		if len(string(s)) > 1 {
-			// detect wide-char
-			siz++
+// parse the boolean string: true/false, yes/no, on/off and 1/0
+func ParseBool(in string) (ok bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(in)) {
+	case "true", "yes", "on", "1":
+		ok = true
+	case "false", "no", "off", "0":
+		ok = false
+	default:
+		err = fmt.Errorf("should pass true/false: %#v", in)
+	}
+	return
+}
+
+// compare the version like v1.2.3, return -1, 0 or 1, the pre-release suffix is ignored
+func compareVersion(a, b string) (cmp int) {
+	parse := func(in string) (vers []int) {
+		in = strings.TrimPrefix(strings.TrimSpace(in), "v")
+		if pos := strings.IndexAny(in, "-+"); pos >= 0 {
+			// remove the pre-release and build metadata
+			in = in[:pos]
+		}
+
+		for _, part := range strings.Split(in, ".") {
+			ver, _ := strconv.Atoi(part)
+			vers = append(vers, ver)
+		}
+		return
+	}
+
+	va, vb := parse(a), parse(b)
+	for idx := 0; idx < len(va) || idx < len(vb); idx++ {
+		x, y := 0, 0
+		if idx < len(va) {
+			x = va[idx]
+		}
+		if idx < len(vb) {
+			y = vb[idx]
+		}
+
+		switch {
+		case x < y:
+			cmp = -1
+			return
+		case x > y:
+			cmp = 1
+			return
 		}
 	}
 	return
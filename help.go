@@ -0,0 +1,384 @@
+package argparse
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// the default template of the help message
+const DEFAULT_HELP_TEMPLATE = `{{if .Error}}{{style "error" "error:"}} {{.Error}}
+{{end}}{{.Usage}}
+{{if .Description}}
+{{wrap .Width .Description}}
+{{end}}{{range .Sections}}
+{{style "header" .Title}}:
+{{if .Description}}{{indent 4 (wrap (sub $.Width 4) .Description)}}
+{{end}}{{range .Entries}}{{.Line}}
+{{end}}{{end}}{{if .Examples}}
+{{style "header" "example"}}:
+{{range .Examples}}{{indent 4 (printf "%v %v" $.Program .Command)}}
+{{if .Description}}{{indent 8 (wrap (sub $.Width 8) .Description)}}
+{{end}}{{end}}{{end}}{{if .Epilog}}
+{{wrap .Width .Epilog}}
+{{end}}`
+
+// the structure provides the customized template of the help message
+type HelpTemplater interface {
+	HelpTemplate() string
+}
+
+// the structured help message rendered by the template
+type HelpModel struct {
+	// the program name and the usage line
+	Program string
+	Usage   string
+	// the error message, empty when no error
+	Error string
+
+	Description string
+	Sections    []HelpSection
+	Examples    []Example
+	Epilog      string
+
+	// the width used to wrap the text, never wrap when 0
+	Width int
+	// the theme used by the style function, plain text when nil
+	Theme *Theme
+}
+
+// the section of the help message, like option, argument, sub-command and the groups
+type HelpSection struct {
+	Title       string
+	Description string
+	Entries     []HelpEntry
+}
+
+// the entry of the field shown in the help message
+type HelpEntry struct {
+	FieldType
+
+	Name       string
+	Shortcut   string
+	Aliases    []string
+	Hint       string
+	Help       string
+	Default    string
+	Choices    []string
+	Deprecated string
+	Hidden     bool
+
+	// the formatted line by the default layout
+	Line string
+}
+
+// the functions can be used in the help template, and the style function
+// "style NAME TEXT" is bound to the theme of the model when render
+var help_funcs = template.FuncMap{
+	// wrap the text within the width
+	"wrap": func(width int, text string) string {
+		return strings.Join(wrapText(text, width), "\n")
+	},
+	// indent each line of the text
+	"indent": func(n int, text string) string {
+		lines := strings.Split(text, "\n")
+		for idx, line := range lines {
+			line = fmt.Sprintf("%*v%v", n, "", line)
+			lines[idx] = strings.TrimRight(line, " \t\n")
+		}
+		return strings.Join(lines, "\n")
+	},
+	// pad the text to the display width
+	"pad": func(width int, text string) string {
+		return padRight(text, width)
+	},
+	"sub": func(a, b int) int {
+		return a - b
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// show the help message without the hidden fields
+func (parser *ArgParse) HelpMessage(err error) {
+	parser.helpMessage(err, false)
+}
+
+// show the help message include the hidden fields
+func (parser *ArgParse) HelpAllMessage(err error) {
+	parser.helpMessage(err, true)
+}
+
+// show the help message of the sub-command path, like "remote add", and show the help message
+// of the parser itself, which lists all the sub-commands, when the path is empty
+func (parser *ArgParse) HelpCommand(names ...string) {
+	target := parser
+	for _, name := range names {
+		field, ok := target.used_subcommand[name]
+		if !ok || field.Subcommand == nil {
+			log.Info("unknown command %v in %v", name, target.program())
+			target.HelpMessage(fmt.Errorf("unknown command: %v%v", name, target.suggestSubcommand(name)))
+			return
+		}
+
+		target.inherit(field.Subcommand)
+		target = field.Subcommand
+	}
+
+	target.HelpMessage(nil)
+}
+
+func (parser *ArgParse) helpMessage(err error, all bool) {
+	model := parser.HelpModel(err, all)
+
+	msg, e := model.Render(parser.HelpTemplate)
+	if e != nil {
+		log.Warn("cannot render help template: %v", e)
+		// fallback to the default template
+		msg, _ = model.Render(DEFAULT_HELP_TEMPLATE)
+	}
+
+	Stderr.WriteString(msg)
+}
+
+// render the help message by the template, use the default template when empty
+func (model HelpModel) Render(text string) (msg string, err error) {
+	if text == "" {
+		// use the default template
+		text = DEFAULT_HELP_TEMPLATE
+	}
+
+	funcs := template.FuncMap{"style": model.Theme.styleBy}
+	for name, fn := range help_funcs {
+		funcs[name] = fn
+	}
+
+	var tmpl *template.Template
+	if tmpl, err = template.New("help").Funcs(funcs).Parse(text); err != nil {
+		return
+	}
+
+	buff := &bytes.Buffer{}
+	if err = tmpl.Execute(buff, model); err != nil {
+		return
+	}
+
+	msg = buff.String()
+	return
+}
+
+// build the structured help message
+func (parser *ArgParse) HelpModel(err error, all bool) (model HelpModel) {
+	model = HelpModel{
+		Program:     parser.program(),
+		Usage:       parser.usage(all),
+		Description: parser.Description,
+		Examples:    parser.Examples,
+		Epilog:      parser.Epilog,
+		Width:       helpWidth(),
+		Theme:       themeFor(parser.Theme, Stderr),
+	}
+
+	if err != nil {
+		// set the error message
+		model.Error = err.Error()
+	}
+
+	options := parser.visible(parser.options, all)
+	if len(options) > 0 {
+		margin, pending, siz := FMT_MARGIN, FMT_PENDING, FMT_SIZE
+
+		for _, field := range options {
+			if field.Shortcut != rune(0) {
+				if p := WidecharSize(string(field.Shortcut)) + WidecharSize(field.TypeHint) + 4; p > pending {
+					// override the pending
+					pending = p
+				}
+			}
+
+			if s := WidecharSize(field.OptionName()) + WidecharSize(field.TypeHint) + 6; s > siz {
+				// override the size
+				siz = s
+			}
+		}
+
+		model.Sections = append(model.Sections, parser.groupSections("option", options, margin, pending, siz, model.Width, model.Theme)...)
+	}
+
+	arguments := parser.arguments
+	if parser.remainder != nil {
+		// the remainder always shown as the last argument
+		arguments = append(arguments[:len(arguments):len(arguments)], parser.remainder)
+	}
+	arguments = parser.visible(arguments, all)
+
+	if len(arguments) > 0 {
+		margin, pending, siz := FMT_MARGIN, FMT_PENDING, FMT_SIZE
+
+		for _, field := range arguments {
+			if s := WidecharSize(field.Name) + WidecharSize(field.TypeHint) + 4; s > siz {
+				// override the size
+				siz = s
+			}
+		}
+
+		model.Sections = append(model.Sections, newHelpSection("argument", "", arguments, margin, pending, siz, model.Width, model.Theme))
+	}
+
+	subcommands := parser.visible(parser.subcommands, all)
+	if len(subcommands) > 0 {
+		margin, pending, siz := FMT_MARGIN, FMT_PENDING, FMT_SIZE
+
+		for _, field := range subcommands {
+			if s := WidecharSize(field.Name) + WidecharSize(field.TypeHint) + 4; s > siz {
+				// override the size
+				siz = s
+			}
+		}
+
+		model.Sections = append(model.Sections, parser.groupSections("sub-command", subcommands, margin, pending, siz, model.Width, model.Theme)...)
+	}
+
+	return
+}
+
+// the section of fields without the group, and then the grouped fields by the order of groups
+func (parser *ArgParse) groupSections(title string, fields []*Field, margin, pending, siz, width int, theme *Theme) (sections []HelpSection) {
+	grouped := map[string][]*Field{}
+	ungrouped := []*Field{}
+	for _, field := range fields {
+		switch field.Group {
+		case "":
+			ungrouped = append(ungrouped, field)
+		default:
+			grouped[field.Group] = append(grouped[field.Group], field)
+		}
+	}
+
+	if len(ungrouped) > 0 {
+		sections = append(sections, newHelpSection(title, "", ungrouped, margin, pending, siz, width, theme))
+	}
+
+	for _, group := range parser.groups {
+		if fields := grouped[group.Name]; len(fields) > 0 {
+			sections = append(sections, newHelpSection(group.Name, group.Description, fields, margin, pending, siz, width, theme))
+		}
+	}
+	return
+}
+
+func newHelpSection(title, description string, fields []*Field, margin, pending, siz, width int, theme *Theme) (section HelpSection) {
+	section = HelpSection{
+		Title:       title,
+		Description: description,
+	}
+
+	for _, field := range fields {
+		log.Debug("format string m:%d, p:%d, s:%d", margin, pending, siz)
+
+		entry := HelpEntry{
+			FieldType:  field.FieldType,
+			Name:       field.Name,
+			Aliases:    field.Aliases,
+			Hint:       field.TypeHint,
+			Help:       field.Help,
+			Choices:    field.Choices,
+			Deprecated: field.Deprecated,
+			Hidden:     field.Hidden,
+			Line:       field.formatString(margin, pending, siz, width, theme),
+		}
+
+		if field.Shortcut != rune(0) {
+			// set the shortcut
+			entry.Shortcut = string(field.Shortcut)
+		}
+
+		if field.DefaultValue != nil {
+			// set the default value
+			entry.Default = fmt.Sprintf("%v", field.redact(field.DefaultValue))
+		}
+
+		section.Entries = append(section.Entries, entry)
+	}
+	return
+}
+
+// the synopsis of the parser: the program path, the options with the mutually exclusive sets like
+// [--json | --yaml], the arguments (the slice one with the ellipsis), the sub-command and the
+// remainder. All of them are in the brackets, since the missing one is never rejected
+func (parser *ArgParse) usage(all bool) (str string) {
+	str = fmt.Sprintf("usage: %v", parser.program())
+
+	exclusive, sets := map[string][]string{}, []string{}
+	options := false
+	for _, field := range parser.visible(parser.options, all) {
+		if field.Exclusive == "" {
+			options = true
+			continue
+		}
+
+		name := "--" + field.Name
+		if !field.IsBool() && field.Action != ACTION_COUNT && field.TypeHint != "" {
+			// the option takes the value
+			name = fmt.Sprintf("%v %v", name, field.TypeHint)
+		}
+
+		if _, ok := exclusive[field.Exclusive]; !ok {
+			// shown by the order of appearance
+			sets = append(sets, field.Exclusive)
+		}
+		exclusive[field.Exclusive] = append(exclusive[field.Exclusive], name)
+	}
+
+	if options {
+		// add the option
+		str = fmt.Sprintf("%v [OPTIONS]", str)
+	}
+	for _, set := range sets {
+		// add the mutually exclusive options
+		str = fmt.Sprintf("%v [%v]", str, strings.Join(exclusive[set], " | "))
+	}
+
+	// add the command
+	for _, field := range parser.visible(parser.arguments, all) {
+		name := strings.ToUpper(field.Name)
+		if field.IsSlice() {
+			// the repeatable argument
+			name = fmt.Sprintf("%v...", name)
+		}
+		str = fmt.Sprintf("%v [%v]", str, name)
+	}
+
+	if len(parser.visible(parser.subcommands, all)) > 0 {
+		// add the sub-command
+		str = fmt.Sprintf("%v [<COMMAND>]", str)
+	}
+
+	if parser.remainder != nil && (all || !parser.remainder.Hidden) {
+		// add the remainder
+		str = fmt.Sprintf("%v [--] [%v...]", str, parser.remainder.Name)
+	}
+
+	return
+}
+
+// the full path of the program, like "git remote add" for the nested sub-command
+func (parser *ArgParse) program() (name string) {
+	if name = parser.Name; parser.path != "" {
+		name = parser.path
+	}
+	return
+}
+
+// filter the fields shown in the help message
+func (parser *ArgParse) visible(fields []*Field, all bool) (visible []*Field) {
+	for _, field := range fields {
+		if all || !field.Hidden {
+			// the field can be shown
+			visible = append(visible, field)
+		}
+	}
+	return
+}
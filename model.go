@@ -13,18 +13,69 @@ type Model struct {
 
 type Help struct {
 	// show the default help message
-	ShowHelp bool `short:"h" name:"help" help:"show this message" callback:"_help"`
+	ShowHelp bool `short:"h" name:"help" help:"show this message" callback:"_help" negate:"false"`
+}
+
+type HelpAll struct {
+	// show the help message include the hidden fields
+	ShowHelpAll bool `name:"help-all" help:"show this message include hidden" callback:"_help_all" negate:"false"`
+}
+
+// the opt-in help sub-command, show the help message of the sub-command path like "help remote add"
+type HelpCommand struct {
+	HelpCommand *HelpPath `name:"help" help:"show the help message of the command" callback:"_help_command"`
+}
+
+type HelpPath struct {
+	// the path of the sub-command names
+	Path []string `args:"remainder"`
+}
+
+// the opt-in option dump the effective configuration after parsed, like --dump-config=yaml
+type DumpConfig struct {
+	DumpFormat string `name:"dump-config" choices:"args env json yaml" help:"dump the effective configuration and exit" callback:"_dump_config"`
 }
 
 type Version struct {
 	// show the version
-	ShowVersion bool `short:"v" name:"version" help:"show argparse version" callback:"_version"`
+	ShowVersion bool `short:"v" name:"version" help:"show the version" callback:"_version" negate:"false"`
+}
+
+// the opt-in option show the version as the format, like --version-format=json
+type VersionFormat struct {
+	ShowVersionFormat string `name:"version-format" choices:"short verbose json" help:"show the version as the format and exit" callback:"_version_format"`
+}
+
+// the example of the command line shown in the help message
+type Example struct {
+	// the command line, without the program name
+	Command     string
+	Description string
+}
+
+// the structure provides the description shown after the usage
+type Describer interface {
+	Description() string
+}
+
+// the structure provides the examples shown after the fields
+type Exampler interface {
+	Examples() []Example
+}
+
+// the structure provides the epilog shown at the end of the help message
+type Epiloger interface {
+	Epilog() string
 }
 
 func init() {
 	// set the default callback
 	RegisterCallback(FN_HELP, defaultHelpMessage)
+	RegisterCallback(FN_HELP_ALL, defaultHelpAllMessage)
+	RegisterCallback(FN_HELP_COMMAND, defaultHelpCommand)
 	RegisterCallback(FN_VERSION, defaultVersionMessage)
+	RegisterCallback(FN_VERSION_FORMAT, defaultVersionFormat)
+	RegisterCallback(FN_DUMP_CONFIG, defaultDumpConfig)
 }
 
 // show the help message and exit
@@ -34,8 +85,72 @@ func defaultHelpMessage(in *ArgParse) (exit bool) {
 	return
 }
 
+// show the help message include the hidden fields and exit
+func defaultHelpAllMessage(in *ArgParse) (exit bool) {
+	in.HelpAllMessage(nil)
+	exit = true
+	return
+}
+
+// show the help message of the sub-command path and exit
+func defaultHelpCommand(in *ArgParse) (exit bool) {
+	for _, field := range in.subcommands {
+		if path, ok := field.Value.Interface().(*HelpPath); ok && field.Callback == FN_HELP_COMMAND {
+			in.HelpCommand(path.Path...)
+			// reset the path for the next parse
+			path.Path = nil
+			break
+		}
+	}
+
+	exit = true
+	return
+}
+
+// show the version of the program as the VersionFormat and exit
 func defaultVersionMessage(in *ArgParse) (exit bool) {
-	os.Stdout.WriteString(fmt.Sprintf("%v (v%d.%d.%d)\n", PROJ_NAME, MAJOR, MINOR, MACRO))
+	in.versionMessage(in.VersionFormat)
 	exit = true
 	return
 }
+
+// show the version of the program as the format passed by --version-format and exit
+func defaultVersionFormat(in *ArgParse) (exit bool) {
+	for _, field := range in.options {
+		if field.Callback == FN_VERSION_FORMAT {
+			in.versionMessage(field.Value.String())
+			break
+		}
+	}
+
+	exit = true
+	return
+}
+
+// write the version of the program as the format, the short form by default
+func (parser *ArgParse) versionMessage(format string) {
+	info := parser.VersionInfo()
+
+	var msg string
+	switch format {
+	case VERSION_VERBOSE:
+		msg = info.Verbose()
+	case VERSION_JSON:
+		msg = info.JSON()
+	default:
+		msg = info.String()
+	}
+
+	os.Stdout.WriteString(fmt.Sprintf("%v\n", msg))
+}
+
+// record the format, and dump the effective configuration after all the arguments are parsed
+func defaultDumpConfig(in *ArgParse) (exit bool) {
+	for _, field := range in.options {
+		if field.Callback == FN_DUMP_CONFIG {
+			in.dump_format = field.Value.String()
+			break
+		}
+	}
+	return
+}
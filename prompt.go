@@ -0,0 +1,99 @@
+package argparse
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// the required field not set yet: the argument without the default value
+func (field *Field) missing() (ok bool) {
+	ok = field.FieldType == ARGUMENT && field.Value.Kind() == reflect.Ptr && field.Value.IsNil()
+	return
+}
+
+// the stdin is the tty which can be prompted, replaced in the test
+var stdinIsTerminal = func() bool { return isTerminal(Stdin.Fd()) }
+
+// ask the missing values on the tty when Interactive, never asked when stopped by the callback
+func (parser *ArgParse) promptInteractive() (err error) {
+	if parser.Interactive && !parser.stopped && stdinIsTerminal() {
+		err = parser.PromptMissing()
+	}
+	return
+}
+
+// prompt the missing required fields from the stdin, and re-prompt when the answer is invalid
+func (parser *ArgParse) PromptMissing() (err error) {
+	for _, field := range parser.arguments {
+		if !field.missing() {
+			// already set or has the default value
+			continue
+		}
+
+		if err = parser.prompt(field); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// prompt the value of the field, the choices are shown as the numbered menu
+func (parser *ArgParse) prompt(field *Field) (err error) {
+	question := field.Name
+	if field.Help != "" {
+		// use the help message as the question
+		question = fmt.Sprintf("%v [%v]", field.Help, field.Name)
+	}
+
+	for {
+		Stderr.WriteString(fmt.Sprintf("%v:\n", question))
+		for idx, choice := range field.Choices {
+			Stderr.WriteString(fmt.Sprintf("%*v%d) %v\n", FMT_MARGIN, "", idx+1, choice))
+		}
+		Stderr.WriteString("> ")
+
+		var answer string
+		if answer, err = readLine(Stdin); err != nil {
+			err = fmt.Errorf("cannot read %v: %v", field.Name, err)
+			return
+		}
+
+		if answer = strings.TrimSpace(answer); answer == "" {
+			// ask again
+			continue
+		}
+
+		pos := sort.SearchStrings(field.Choices, answer)
+		exact := pos < len(field.Choices) && field.Choices[pos] == answer
+		if idx, e := strconv.Atoi(answer); e == nil && !exact && idx > 0 && idx <= len(field.Choices) {
+			// choose by the number of the menu, unless the answer is the choice itself
+			answer = field.Choices[idx-1]
+		}
+
+		values := []string{answer}
+		if field.IsSlice() {
+			// the repeatable argument separated by the space
+			values = strings.Fields(answer)
+		}
+
+		// set each value, and restore when any of them is invalid
+		saved := cloneValue(field.Value)
+		for _, value := range values {
+			if _, err = field.SetValue(parser, value); err != nil {
+				break
+			}
+		}
+
+		if err != nil {
+			field.Value.Set(saved)
+			log.Info("invalid answer of %v: %v", field.Name, err)
+			Stderr.WriteString(fmt.Sprintf("error: %v\n", err))
+			continue
+		}
+
+		return
+	}
+}
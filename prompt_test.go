@@ -0,0 +1,81 @@
+package argparse
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// feed the answers as the stdin on the tty, and discard the questions
+func fakePrompt(t *testing.T, answers string) (restore func()) {
+	stdin, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	stdin.WriteString(answers)
+	stdin.Seek(0, 0)
+
+	stderr, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+
+	saved_stdin, saved_stderr, saved_terminal := Stdin, Stderr, stdinIsTerminal
+	Stdin, Stderr, stdinIsTerminal = stdin, stderr, func() bool { return true }
+	restore = func() {
+		Stdin, Stderr, stdinIsTerminal = saved_stdin, saved_stderr, saved_terminal
+		os.Remove(stdin.Name())
+		os.Remove(stderr.Name())
+	}
+	return
+}
+
+func TestPromptChoice(t *testing.T) {
+	defer fakePrompt(t, "2\n1\n")()
+
+	c := struct {
+		Port  *string `choices:"2 3"`
+		Level *string `choices:"2 3"`
+	}{}
+	parser := MustNew(&c)
+	if err := parser.PromptMissing(); err != nil {
+		t.Fatalf("cannot prompt: %v", err)
+	} else if *c.Port != "2" || *c.Level != "2" {
+		t.Errorf("the exact choice should win over the menu index: %v %v", *c.Port, *c.Level)
+	}
+}
+
+func TestPromptRemainder(t *testing.T) {
+	defer fakePrompt(t, "bob\n")()
+
+	c := struct {
+		Name *string
+		Rest []string `args:"remainder"`
+	}{}
+	parser := MustNew(&c)
+	parser.Interactive = true
+	if err := parser.Parse("--", "x"); err != nil {
+		t.Fatalf("cannot parse -- x: %v", err)
+	} else if c.Name == nil || *c.Name != "bob" || !reflect.DeepEqual(c.Rest, []string{"x"}) {
+		t.Errorf("prompt before the remainder: %v %#v", c.Name, c.Rest)
+	}
+}
+
+func TestPromptSubcommand(t *testing.T) {
+	defer fakePrompt(t, "bob\n")()
+
+	c := struct {
+		Name *string
+		Run  *struct {
+			Force bool
+		}
+	}{}
+	parser := MustNew(&c)
+	parser.Interactive = true
+	if err := parser.Parse("run", "--force"); err != nil {
+		t.Fatalf("cannot parse run --force: %v", err)
+	} else if c.Name == nil || *c.Name != "bob" || c.Run == nil || !c.Run.Force {
+		t.Errorf("prompt with the sub-command: %v %#v", c.Name, c.Run)
+	}
+}
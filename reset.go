@@ -0,0 +1,64 @@
+package argparse
+
+import (
+	"reflect"
+)
+
+// reset the registered fields as the snapshot when New, include the nested sub-commands, and the
+// parser can be reused to parse again. The field not processed by the parser is never touched
+func (parser *ArgParse) Reset() {
+	log.Info("reset %v", parser.Name)
+
+	fields := append(parser.options[:len(parser.options):len(parser.options)], parser.arguments...)
+	fields = append(fields, parser.subcommands...)
+	if parser.remainder != nil {
+		fields = append(fields, parser.remainder)
+	}
+
+	for _, field := range fields {
+		if field.initial.IsValid() {
+			// restore the value by the copy of the snapshot
+			field.Value.Set(cloneValue(field.initial))
+		}
+
+		field.BeenSet, field.warned = false, false
+		if field.Subcommand != nil {
+			// reset the nested sub-command
+			field.Subcommand.Reset()
+		}
+	}
+
+	parser.unknown, parser.stopped, parser.dump_format = nil, false, ""
+}
+
+// copy the value deeply, except the pointer of the structure, like the sub-command and the
+// *os.File, which are shared
+func cloneValue(value reflect.Value) (clone reflect.Value) {
+	clone = reflect.New(value.Type()).Elem()
+	clone.Set(value)
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() && value.Elem().Kind() != reflect.Struct {
+			ptr := reflect.New(value.Type().Elem())
+			ptr.Elem().Set(cloneValue(value.Elem()))
+			clone.Set(ptr)
+		}
+	case reflect.Slice:
+		if !value.IsNil() {
+			slice := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+			for idx := 0; idx < value.Len(); idx++ {
+				slice.Index(idx).Set(cloneValue(value.Index(idx)))
+			}
+			clone.Set(slice)
+		}
+	case reflect.Struct:
+		for idx := 0; idx < value.NumField(); idx++ {
+			if field := clone.Field(idx); field.CanSet() {
+				// only the exported field can be copied
+				field.Set(cloneValue(value.Field(idx)))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,71 @@
+package argparse
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// expand the response file, like @args.txt, into the arguments from the file. The file is split
+// by the shell-like quoting per line, and the relative path in the file is resolved from the
+// directory of the file
+func (parser *ArgParse) expandResponse(args []string, dir string, depth int) (expanded []string, err error) {
+	prefix := parser.ResponsePrefix
+
+	for idx, token := range args {
+		switch {
+		case token == "--":
+			// the tokens after -- are never expanded
+			expanded = append(expanded, args[idx:]...)
+			return
+		case len(token) > len(prefix) && strings.HasPrefix(token, prefix):
+			path := token[len(prefix):]
+			if !filepath.IsAbs(path) && dir != "" {
+				// relative to the response file
+				path = filepath.Join(dir, path)
+			}
+
+			if depth >= RESPONSE_MAX_DEPTH {
+				err = fmt.Errorf("response file %v: too many nested response files", path)
+				return
+			}
+
+			var tokens []string
+			if tokens, err = parser.readResponse(path, depth); err != nil {
+				return
+			}
+			expanded = append(expanded, tokens...)
+		default:
+			expanded = append(expanded, token)
+		}
+	}
+	return
+}
+
+// read the arguments from the response file, and expand the nested response file
+func (parser *ArgParse) readResponse(path string, depth int) (args []string, err error) {
+	log.Info("read response file %v", path)
+
+	var data []byte
+	if data, err = ioutil.ReadFile(path); err != nil {
+		err = fmt.Errorf("cannot read response file %v: %w", path, err)
+		return
+	}
+
+	for idx, line := range strings.Split(string(data), "\n") {
+		var tokens []string
+		if tokens, err = SplitArgs(strings.TrimSuffix(line, "\r"), nil); err != nil {
+			err = fmt.Errorf("response file %v:%d: %v", path, idx+1, err)
+			return
+		}
+
+		if tokens, err = parser.expandResponse(tokens, filepath.Dir(path), depth+1); err != nil {
+			// the position of the nested response file
+			err = fmt.Errorf("response file %v:%d: %w", path, idx+1, err)
+			return
+		}
+		args = append(args, tokens...)
+	}
+	return
+}
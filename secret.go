@@ -0,0 +1,205 @@
+package argparse
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+)
+
+var (
+	// the input used to read the secret and the prompt
+	Stdin = os.Stdin
+)
+
+// the secret value which is redacted when shown, use string(secret) to get the raw value
+type Secret string
+
+// the redacted secret
+func (secret Secret) String() (str string) {
+	if secret != "" {
+		// never show the secret
+		str = SECRET_MASK
+	}
+	return
+}
+
+// the redacted secret in the %#v format
+func (secret Secret) GoString() (str string) {
+	str = fmt.Sprintf("argparse.Secret(%q)", secret.String())
+	return
+}
+
+// check the type is Secret or *Secret
+func isSecret(typ reflect.Type) (ok bool) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	ok = typ == reflect.TypeOf(Secret(""))
+	return
+}
+
+// the structure field is the secret, the Secret type or tagged as the password
+func isSecretField(sfield reflect.StructField) (ok bool) {
+	ok = sfield.Tag.Get(TAG_RESERVED_KEY) == KEY_PASSWORD || isSecret(sfield.Type)
+	return
+}
+
+// the value of the field is redacted when the field is secret
+func (field *Field) redact(value interface{}) (redacted interface{}) {
+	if redacted = value; field.Secret {
+		// never show the secret
+		redacted = SECRET_MASK
+	}
+	return
+}
+
+// set the secret from the arguments, prompt on the tty when the value is omitted or "-", and the
+// next option or sub-command is never taken as the value
+func (field *Field) setSecret(parser *ArgParse, args ...string) (size int, err error) {
+	source := ""
+	if len(args) > 0 && (args[0] == "-" || !strings.HasPrefix(args[0], "-")) {
+		if _, ok := parser.used_subcommand[args[0]]; !ok {
+			// the value is passed
+			source, size = args[0], 1
+		}
+	}
+
+	err = field.setSecretSource(source)
+	return
+}
+
+// set the secret by the source, like the openssl pass phrase arguments
+//
+//	"" or "-"  prompt on the tty with echo disabled
+//	stdin      read the first line from the stdin
+//	env:NAME   read from the environment variable
+//	file:PATH  read the first line from the file
+//	pass:VALUE the value itself
+func (field *Field) setSecretSource(source string) (err error) {
+	var secret string
+
+	switch {
+	case source == "" || source == "-":
+		prompt := fmt.Sprintf("%v: ", field.DisplayName())
+		if secret, err = promptSecret(prompt); err != nil {
+			return
+		}
+	case source == "stdin":
+		if secret, err = readLine(Stdin); err != nil {
+			err = fmt.Errorf("cannot read secret from stdin: %v", err)
+			return
+		}
+	case strings.HasPrefix(source, "env:"):
+		var ok bool
+		if secret, ok = os.LookupEnv(source[4:]); !ok {
+			err = fmt.Errorf("environment variable %v not set", source[4:])
+			return
+		}
+	case strings.HasPrefix(source, "file:"):
+		var data []byte
+		if data, err = ioutil.ReadFile(source[5:]); err != nil {
+			err = fmt.Errorf("cannot read secret file %v", source[5:])
+			return
+		}
+		secret = strings.SplitN(string(data), "\n", 2)[0]
+		secret = strings.TrimSuffix(secret, "\r")
+	case strings.HasPrefix(source, "pass:"):
+		secret = source[5:]
+	default:
+		secret = source
+	}
+
+	value := field.Value
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			// nil pointer, new instance
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+	value.SetString(secret)
+
+	log.Info("set secret %v", field.Name)
+	return
+}
+
+// prompt on the tty and read the secret with echo disabled
+func promptSecret(prompt string) (secret string, err error) {
+	if !isTerminal(Stdin.Fd()) {
+		err = fmt.Errorf("cannot prompt secret: not a terminal")
+		return
+	}
+
+	Stderr.WriteString(prompt)
+	secret, err = readPassword(Stdin.Fd())
+	// the newline is not echoed
+	Stderr.WriteString("\n")
+	return
+}
+
+// read the line from the file without the newline
+func readLine(f *os.File) (line string, err error) {
+	buff := []byte{0}
+	for {
+		if _, err = f.Read(buff); err != nil {
+			if err == io.EOF && line != "" {
+				// the last line without the newline
+				err = nil
+			}
+			break
+		}
+
+		if buff[0] == '\n' {
+			break
+		}
+		line += string(buff)
+	}
+
+	line = strings.TrimSuffix(line, "\r")
+	return
+}
+
+// redact the secret options in the arguments, used in the log
+func (parser *ArgParse) redactArgs(args []string) (redacted []string) {
+	redacted = append([]string{}, args...)
+
+	for idx := 0; idx < len(redacted); idx++ {
+		var field *Field
+
+		token := redacted[idx]
+		switch {
+		case token == "--":
+			return
+		case strings.HasPrefix(token, "--"):
+			if pos := strings.Index(token, "="); pos >= 0 {
+				if field, ok := parser.used_option[token[:pos]]; ok && field.Secret {
+					// --NAME=SECRET
+					redacted[idx] = token[:pos+1] + SECRET_MASK
+				}
+				continue
+			}
+			field = parser.used_option[token]
+		case strings.HasPrefix(token, "-") && len(token) > 1:
+			// the last shortcut may take the value
+			shortcuts := []rune(token[1:])
+			field = parser.used_shortcut[shortcuts[len(shortcuts)-1]]
+		default:
+			if field, ok := parser.used_subcommand[token]; ok && field.Subcommand != nil {
+				// redact by the sub-command
+				copy(redacted[idx+1:], field.Subcommand.redactArgs(redacted[idx+1:]))
+				return
+			}
+		}
+
+		if field != nil && field.Secret && idx+1 < len(redacted) && !strings.HasPrefix(redacted[idx+1], "-") {
+			// the value of the secret option
+			redacted[idx+1] = SECRET_MASK
+			idx++
+		}
+	}
+	return
+}
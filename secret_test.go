@@ -0,0 +1,53 @@
+package argparse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// record the log messages
+type recordLogger struct {
+	messages []string
+}
+
+func (logger *recordLogger) record(format string, args ...interface{}) {
+	logger.messages = append(logger.messages, fmt.Sprintf(format, args...))
+}
+
+func (logger *recordLogger) Info(format string, args ...interface{}) { logger.record(format, args...) }
+func (logger *recordLogger) Verbose(format string, args ...interface{}) {
+	logger.record(format, args...)
+}
+func (logger *recordLogger) Debug(format string, args ...interface{}) { logger.record(format, args...) }
+func (logger *recordLogger) Warn(format string, args ...interface{})  { logger.record(format, args...) }
+
+func TestSecretLog(t *testing.T) {
+	defer func(l logging) { log = l }(log)
+	recorder := &recordLogger{}
+	log = recorder
+
+	// the plain string password and the Secret with the pre-filled default
+	c := struct {
+		Name     string
+		Password string `args:"password"`
+		Token    Secret
+	}{Password: "hunter1", Token: "hunter0"}
+	parser := MustNew(&c)
+	if err := parser.Parse("--name", "bob", "--password", "hunter2"); err != nil {
+		t.Fatalf("cannot parse the secret: %v", err)
+	} else if err := parser.Parse("--password=hunter3", "--name", "alice"); err != nil {
+		t.Fatalf("cannot parse the explicit secret: %v", err)
+	} else if c.Password != "hunter3" || c.Name != "alice" {
+		t.Fatalf("parse the secret: %#v", c)
+	}
+
+	if len(recorder.messages) == 0 {
+		t.Fatalf("expect the log messages")
+	}
+	for _, msg := range recorder.messages {
+		if strings.Contains(msg, "hunter") {
+			t.Errorf("the secret should never be logged: %v", msg)
+		}
+	}
+}
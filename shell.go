@@ -0,0 +1,299 @@
+package argparse
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// the built-in commands of the shell
+const (
+	SHELL_EXIT    = "exit"
+	SHELL_HELP    = "help"
+	SHELL_HISTORY = "history"
+)
+
+// the handler executed after each line parsed, pass the fresh structure of the parser
+type ShellHandler func(in interface{}) error
+
+// run the interactive shell: read the line, split by the shell-like quoting and parse with the
+// value reset per line, and then execute the handler. It never exits the process on the error
+// or --help, and stops by the exit or EOF
+func (parser *ArgParse) Shell(handler ShellHandler) (err error) {
+	defer func(no_exit bool) {
+		// restore the setting
+		parser.no_exit = no_exit
+	}(parser.no_exit)
+	parser.no_exit = true
+
+	editor := &lineEditor{
+		prompt:   fmt.Sprintf("%v> ", parser.Name),
+		complete: parser.completeLine,
+	}
+
+	for {
+		var line string
+		if line, err = editor.readLine(); err != nil {
+			if err == io.EOF {
+				// end of the session
+				err = nil
+			}
+			return
+		}
+
+		args, e := SplitArgs(line, nil)
+		if e != nil {
+			Stderr.WriteString(fmt.Sprintf("error: %v\n", e))
+			continue
+		} else if len(args) == 0 {
+			// empty line
+			continue
+		}
+		editor.history = append(editor.history, line)
+
+		switch args[0] {
+		case SHELL_EXIT:
+			log.Info("exit the shell")
+			return
+		case SHELL_HISTORY:
+			for idx, line := range editor.history {
+				Stderr.WriteString(fmt.Sprintf("%*d  %v\n", FMT_MARGIN+1, idx+1, line))
+			}
+			continue
+		}
+
+		// start from the default value
+		parser.Reset()
+
+		if args[0] == SHELL_HELP {
+			parser.HelpCommand(args[1:]...)
+			continue
+		}
+
+		if e := parser.Parse(args...); e != nil {
+			Stderr.WriteString(fmt.Sprintf("error: %v\n", e))
+			continue
+		} else if parser.stopped {
+			// the callback is executed, like --help
+			continue
+		}
+
+		if handler != nil {
+			if e := handler(parser.Value.Interface()); e != nil {
+				Stderr.WriteString(fmt.Sprintf("error: %v\n", e))
+			}
+		}
+	}
+}
+
+// the completion candidates of the line, complete the last word
+func (parser *ArgParse) completeLine(line string) (candidates []string) {
+	args, err := SplitArgs(line, nil)
+	if err != nil {
+		// the incomplete quoting, split by the space
+		args = strings.Fields(line)
+	}
+
+	prefix := ""
+	if len(args) > 0 && !strings.HasSuffix(line, " ") {
+		// complete the last word
+		args, prefix = args[:len(args)-1], args[len(args)-1]
+	}
+
+	words := parser.Complete(args...)
+	if len(args) == 0 {
+		// the built-in commands
+		words = append(words, SHELL_EXIT, SHELL_HELP, SHELL_HISTORY)
+	}
+
+	for _, word := range words {
+		if strings.HasPrefix(word, prefix) {
+			candidates = append(candidates, word)
+		}
+	}
+
+	sort.Strings(candidates)
+	return
+}
+
+// the candidates of the next word after the arguments: the choices of the option which takes
+// the value, or the long options, the sub-commands and the choices of the next argument
+func (parser *ArgParse) Complete(args ...string) (words []string) {
+	target := parser
+	for idx, arg := range args {
+		if field, ok := target.used_subcommand[arg]; ok && field.Subcommand != nil {
+			// complete on the sub-command
+			target = field.Subcommand
+			continue
+		}
+
+		if field, ok := target.used_option[arg]; ok && idx == len(args)-1 && !field.IsBool() && field.Action != ACTION_COUNT {
+			// the option takes the value
+			words = append(words, field.Choices...)
+			return
+		}
+	}
+
+	for name, field := range target.used_option {
+		if !field.Hidden && !field.isHiddenAlias(name[2:]) {
+			words = append(words, name)
+		}
+	}
+
+	for _, field := range target.subcommands {
+		if !field.Hidden {
+			words = append(words, field.Name)
+		}
+	}
+
+	for _, field := range target.arguments {
+		if field.missing() {
+			// the choices of the next argument
+			words = append(words, field.Choices...)
+			break
+		}
+	}
+
+	sort.Strings(words)
+	return
+}
+
+// the simple line editor with the history and the completion on the tty, and read the line
+// directly when not a tty
+type lineEditor struct {
+	prompt   string
+	history  []string
+	complete func(line string) []string
+}
+
+func (editor *lineEditor) readLine() (line string, err error) {
+	fd := Stdin.Fd()
+	if !isTerminal(fd) {
+		// not the tty, read the line directly
+		line, err = readLine(Stdin)
+		return
+	}
+
+	var restore func()
+	if restore, err = makeRaw(fd); err != nil {
+		// cannot set the raw mode, read the line with the prompt
+		Stderr.WriteString(editor.prompt)
+		line, err = readLine(Stdin)
+		return
+	}
+	defer restore()
+
+	buff, index := []rune{}, len(editor.history)
+	redraw := func() {
+		Stderr.WriteString(fmt.Sprintf("\r\x1b[K%v%v", editor.prompt, string(buff)))
+	}
+
+	redraw()
+	for {
+		var b byte
+		if b, err = readByte(fd); err != nil {
+			return
+		}
+
+		switch b {
+		case '\r', '\n':
+			Stderr.WriteString("\n")
+			line = string(buff)
+			return
+		case 3: // Ctrl-C, drop the line
+			Stderr.WriteString("^C\n")
+			buff = buff[:0]
+			redraw()
+		case 4: // Ctrl-D, exit on the empty line
+			if len(buff) == 0 {
+				Stderr.WriteString("\n")
+				err = io.EOF
+				return
+			}
+		case 8, 127: // backspace
+			if len(buff) > 0 {
+				buff = buff[:len(buff)-1]
+				redraw()
+			}
+		case '\t':
+			buff = editor.completion(buff)
+			redraw()
+		case 0x1b: // the escape sequence, only the up and down arrow
+			seq := []byte{0, 0}
+			if seq[0], err = readByte(fd); err != nil {
+				return
+			} else if seq[1], err = readByte(fd); err != nil {
+				return
+			}
+
+			switch {
+			case seq[0] != '[':
+			case seq[1] == 'A' && index > 0:
+				index--
+				buff = []rune(editor.history[index])
+			case seq[1] == 'B' && index < len(editor.history):
+				if index++; index == len(editor.history) {
+					buff = buff[:0]
+				} else {
+					buff = []rune(editor.history[index])
+				}
+			}
+			redraw()
+		default:
+			if b < 0x20 {
+				// ignore the control character
+				continue
+			}
+
+			raw := []byte{b}
+			for !utf8.FullRune(raw) {
+				if b, err = readByte(fd); err != nil {
+					return
+				}
+				raw = append(raw, b)
+			}
+
+			r, _ := utf8.DecodeRune(raw)
+			buff = append(buff, r)
+			Stderr.WriteString(string(r))
+		}
+	}
+}
+
+// complete the last word of the line, list the candidates when ambiguous
+func (editor *lineEditor) completion(buff []rune) (completed []rune) {
+	completed = buff
+	if editor.complete == nil {
+		return
+	}
+
+	line := string(buff)
+	candidates := editor.complete(line)
+	if len(candidates) == 0 {
+		return
+	}
+
+	word := ""
+	if fields := strings.Fields(line); len(fields) > 0 && !strings.HasSuffix(line, " ") {
+		word = fields[len(fields)-1]
+	}
+
+	common := candidates[0]
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	switch {
+	case len(candidates) == 1:
+		completed = []rune(line[:len(line)-len(word)] + common + " ")
+	case len(common) > len(word):
+		completed = []rune(line[:len(line)-len(word)] + common)
+	default:
+		Stderr.WriteString(fmt.Sprintf("\n%v\n", strings.Join(candidates, "  ")))
+	}
+	return
+}
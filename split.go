@@ -0,0 +1,180 @@
+package argparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// split the string into the arguments like the POSIX shell: the single quote keeps the literal,
+// the double quote and the backslash escape the character, the backslash-newline continues the
+// line and # starts the comment until the end of line. The $NAME and ${NAME} outside the single
+// quote are expanded by the lookup function, and never expanded when the lookup is nil
+func SplitArgs(line string, lookup func(string) (string, bool)) (args []string, err error) {
+	runes := []rune(line)
+
+	var quote rune
+	token, in_token, quoted_at := []rune{}, false, 0
+	for pos := 0; pos < len(runes); pos++ {
+		r := runes[pos]
+
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+				continue
+			}
+			token = append(token, r)
+		case r == '\\':
+			if pos+1 == len(runes) {
+				err = fmt.Errorf("unexpected end after the backslash at column %d", pos+1)
+				return
+			}
+
+			pos++
+			switch next := runes[pos]; {
+			case next == '\n':
+				// the line continuation
+			case quote == '"' && !strings.ContainsRune("\"\\$`", next):
+				// keep the backslash in the double quote
+				token, in_token = append(token, r, next), true
+			default:
+				token, in_token = append(token, next), true
+			}
+		case r == '$' && lookup != nil:
+			var value string
+			var size int
+			if value, size, err = expandVar(runes[pos:], lookup); err != nil {
+				err = fmt.Errorf("%v at column %d", err, pos+1)
+				return
+			}
+
+			token, in_token = append(token, []rune(value)...), true
+			pos += size - 1
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+				continue
+			}
+			token = append(token, r)
+		case r == '\'' || r == '"':
+			quote, quoted_at, in_token = r, pos, true
+		case r == '#' && !in_token:
+			// the comment until the end of line
+			for pos+1 < len(runes) && runes[pos+1] != '\n' {
+				pos++
+			}
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if in_token {
+				args = append(args, string(token))
+			}
+			token, in_token = token[:0], false
+		default:
+			token, in_token = append(token, r), true
+		}
+	}
+
+	switch {
+	case quote != 0:
+		err = fmt.Errorf("unterminated quote %c at column %d", quote, quoted_at+1)
+	case in_token:
+		args = append(args, string(token))
+	}
+	return
+}
+
+// expand the variable $NAME or ${NAME} from the head of the runes, the $ is kept when not
+// followed by the name
+func expandVar(runes []rune, lookup func(string) (string, bool)) (value string, size int, err error) {
+	var name string
+
+	switch {
+	case len(runes) > 1 && runes[1] == '{':
+		end := 2
+		for end < len(runes) && runes[end] != '}' {
+			end++
+		}
+
+		if end == len(runes) {
+			err = fmt.Errorf("unterminated ${")
+			return
+		}
+
+		if name, size = string(runes[2:end]), end+1; !isVarName(name) {
+			err = fmt.Errorf("bad substitution ${%v}", name)
+			return
+		}
+	default:
+		size = 1
+		for size < len(runes) && isVarName(string(runes[1:size+1])) {
+			size++
+		}
+
+		if size == 1 {
+			// not the variable
+			value = "$"
+			return
+		}
+		name = string(runes[1:size])
+	}
+
+	value, _ = lookup(name)
+	return
+}
+
+// the variable name: the letter, digit and underscore, and not start with the digit
+func isVarName(name string) (ok bool) {
+	for idx, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && idx > 0:
+		default:
+			return
+		}
+	}
+
+	ok = name != ""
+	return
+}
+
+// join the arguments as the command line, the argument is quoted by the single quote when it
+// contains the special character, and can be split back by SplitArgs
+func JoinArgs(args []string) (line string) {
+	quoted := make([]string, len(args))
+	for idx, arg := range args {
+		quoted[idx] = quoteArg(arg)
+	}
+
+	line = strings.Join(quoted, " ")
+	return
+}
+
+// quote the argument by the single quote when needed, and the single quote inside is escaped by
+// closing the quote, the backslash and re-opening the quote
+func quoteArg(arg string) (quoted string) {
+	safe := func(r rune) (ok bool) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			ok = true
+		default:
+			ok = strings.ContainsRune("-_./:=,+@%", r)
+		}
+		return
+	}
+
+	if quoted = arg; arg == "" || strings.IndexFunc(arg, func(r rune) bool { return !safe(r) }) >= 0 {
+		quoted = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return
+}
+
+// split the string into the arguments and parse, the variable is expanded by the LookupEnv of
+// the parser
+func (parser *ArgParse) ParseString(line string) (err error) {
+	var args []string
+	if args, err = SplitArgs(line, parser.LookupEnv); err != nil {
+		return
+	}
+
+	err = parser.Parse(args...)
+	return
+}
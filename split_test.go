@@ -0,0 +1,84 @@
+package argparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	env := map[string]string{"HOME": "/home/user", "NAME": "a b", "EMPTY": ""}
+	lookup := func(name string) (value string, ok bool) {
+		value, ok = env[name]
+		return
+	}
+
+	cases := []struct {
+		name string
+		in   string
+		args []string
+	}{
+		{"empty", "", nil},
+		{"spaces", "  \t ", nil},
+		{"words", "a  b\tc", []string{"a", "b", "c"}},
+		{"single quote", `'a b' 'c\d'`, []string{"a b", `c\d`}},
+		{"double quote", `"a b" "say \"hi\"" "c\d"`, []string{"a b", `say "hi"`, `c\d`}},
+		{"adjacent quotes", `a'b'"c"`, []string{"abc"}},
+		{"empty quotes", `'' ""`, []string{"", ""}},
+		{"backslash", `a\ b \'c`, []string{"a b", "'c"}},
+		{"line continuation", "a\\\nb c", []string{"ab", "c"}},
+		{"comment", "a # comment\nb", []string{"a", "b"}},
+		{"hash in word", "a#b", []string{"a#b"}},
+		{"variable", "$HOME/bin ${HOME}", []string{"/home/user/bin", "/home/user"}},
+		{"variable in double quote", `"$NAME" $NAME`, []string{"a b", "a b"}},
+		{"variable in single quote", `'$HOME'`, []string{"$HOME"}},
+		{"escaped variable", `\$HOME "\$HOME"`, []string{"$HOME", "$HOME"}},
+		{"unset variable", "x$UNSET-y $EMPTY", []string{"x-y", ""}},
+		{"not variable", "$ $1 a$", []string{"$", "$1", "a$"}},
+	}
+
+	for _, c := range cases {
+		if args, err := SplitArgs(c.in, lookup); err != nil {
+			t.Errorf("%v: SplitArgs(%#v): %v", c.name, c.in, err)
+		} else if !reflect.DeepEqual(args, c.args) {
+			t.Errorf("%v: SplitArgs(%#v) = %#v, expect %#v", c.name, c.in, args, c.args)
+		}
+	}
+
+	if args, _ := SplitArgs("$HOME", nil); !reflect.DeepEqual(args, []string{"$HOME"}) {
+		t.Errorf("never expand without the lookup: %#v", args)
+	}
+}
+
+func TestSplitArgsError(t *testing.T) {
+	cases := []struct {
+		in  string
+		err string
+	}{
+		{`a 'b`, "unterminated quote ' at column 3"},
+		{`a "b\"`, "unterminated quote \" at column 3"},
+		{`a\`, "unexpected end after the backslash at column 2"},
+		{`${HOME`, "unterminated ${ at column 1"},
+		{`a ${1x}`, "bad substitution ${1x} at column 3"},
+	}
+
+	for _, c := range cases {
+		if _, err := SplitArgs(c.in, func(string) (string, bool) { return "", false }); err == nil || err.Error() != c.err {
+			t.Errorf("SplitArgs(%#v) error: %v, expect %v", c.in, err, c.err)
+		}
+	}
+}
+
+func TestJoinArgs(t *testing.T) {
+	args := []string{"plain", "--name=a/b.c", "", "a b", "it's", `"$HOME"`, "#x", "a\nb", "\\"}
+	line := JoinArgs(args)
+	if ans := `plain --name=a/b.c '' 'a b' 'it'\''s' '"$HOME"' '#x' 'a` + "\n" + `b' '\'`; line != ans {
+		t.Errorf("join %#v: %#v", args, line)
+	}
+
+	lookup := func(name string) (string, bool) { return "oops", true }
+	if split, err := SplitArgs(line, lookup); err != nil {
+		t.Fatalf("cannot split %#v: %v", line, err)
+	} else if !reflect.DeepEqual(split, args) {
+		t.Errorf("join should be split back: %#v", split)
+	}
+}
@@ -0,0 +1,90 @@
+package argparse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// the maximal edit distance of the suggestion, disable the suggestion when 0
+	SuggestThreshold = 2
+)
+
+// the suggestion message like ", did you mean --verbose?", or empty when nothing similar
+func suggestMessage(target string, candidates []string) (msg string) {
+	if suggestions := Suggest(target, candidates); len(suggestions) > 0 {
+		// show the suggestion
+		msg = fmt.Sprintf(", did you mean %v?", joinOr(suggestions))
+	}
+	return
+}
+
+// find the most similar candidates by the edit distance within SuggestThreshold
+func Suggest(target string, candidates []string) (suggestions []string) {
+	best := SuggestThreshold + 1
+	for _, candidate := range candidates {
+		distance := editDistance(target, candidate)
+		if distance > SuggestThreshold || distance >= len([]rune(candidate)) {
+			// too different from the candidate
+			continue
+		}
+
+		switch {
+		case distance < best:
+			best, suggestions = distance, []string{candidate}
+		case distance == best:
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	sort.Strings(suggestions)
+	return
+}
+
+// join the candidates as "a, b or c"
+func joinOr(candidates []string) (str string) {
+	switch last := len(candidates) - 1; last {
+	case -1:
+	case 0:
+		str = candidates[0]
+	default:
+		str = strings.Join(candidates[:last], ", ") + " or " + candidates[last]
+	}
+	return
+}
+
+// the Levenshtein distance between two strings
+func editDistance(a, b string) (distance int) {
+	x, y := []rune(a), []rune(b)
+
+	prev := make([]int, len(y)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		curr := make([]int, len(y)+1)
+		curr[0] = i
+
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			curr[j] = prev[j-1] + cost
+			if v := prev[j] + 1; v < curr[j] {
+				curr[j] = v
+			}
+			if v := curr[j-1] + 1; v < curr[j] {
+				curr[j] = v
+			}
+		}
+
+		prev = curr
+	}
+
+	distance = prev[len(y)]
+	return
+}
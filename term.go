@@ -0,0 +1,93 @@
+package argparse
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	// the maximal width of the help message, no limit when 0
+	HelpMaxWidth = 120
+	// the fixed width of the help message, detected from the terminal when 0 and never wrap when negative
+	HelpWidth = 0
+)
+
+// the width of the terminal from the environment COLUMNS or the tty, 0 when unknown or not a tty
+func TerminalWidth(f *os.File) (width int) {
+	if f == nil || !isTerminal(f.Fd()) {
+		// not a terminal, like redirected to the file
+		return
+	}
+
+	if columns := strings.TrimSpace(os.Getenv("COLUMNS")); columns != "" {
+		if w, err := strconv.Atoi(columns); err == nil && w > 0 {
+			width = w
+			return
+		}
+	}
+
+	// get the window size from the tty
+	width = ttyWidth(f.Fd())
+	return
+}
+
+// the width used to wrap the help message, 0 means never wrap
+func helpWidth() (width int) {
+	switch {
+	case HelpWidth < 0:
+		// never wrap
+		return
+	case HelpWidth > 0:
+		width = HelpWidth
+	default:
+		width = TerminalWidth(Stderr)
+	}
+
+	if HelpMaxWidth > 0 && width > HelpMaxWidth {
+		// limit the width
+		width = HelpMaxWidth
+	}
+	return
+}
+
+// wrap the text into lines within the width, and keep the explicit newline
+func wrapText(text string, width int) (lines []string) {
+	for _, paragraph := range strings.Split(text, "\n") {
+		if width <= 0 {
+			// never wrap
+			lines = append(lines, strings.TrimSpace(paragraph))
+			continue
+		}
+
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for WidecharSize(word) > width {
+				// the word too long, break by rune
+				head, siz := "", 0
+				for _, r := range word {
+					if head != "" && siz+WidecharSize(string(r)) > width {
+						break
+					}
+					head, siz = head+string(r), siz+WidecharSize(string(r))
+				}
+
+				if line != "" {
+					lines, line = append(lines, line), ""
+				}
+				lines, word = append(lines, head), word[len(head):]
+			}
+
+			switch {
+			case line == "":
+				line = word
+			case WidecharSize(line)+1+WidecharSize(word) > width:
+				lines, line = append(lines, line), word
+			default:
+				line = line + " " + word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return
+}
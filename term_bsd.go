@@ -0,0 +1,12 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+// +build darwin freebsd netbsd openbsd dragonfly
+
+package argparse
+
+import "syscall"
+
+// the ioctl request to get and set the terminal attributes
+const (
+	ioctl_get_termios = syscall.TIOCGETA
+	ioctl_set_termios = syscall.TIOCSETA
+)
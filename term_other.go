@@ -0,0 +1,36 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package argparse
+
+import (
+	"fmt"
+)
+
+// the window size is not supported, never wrap
+func ttyWidth(fd uintptr) (width int) {
+	return
+}
+
+// the tty is not supported, never styled
+func isTerminal(fd uintptr) (ok bool) {
+	return
+}
+
+// the tty is not supported, cannot prompt the secret
+func readPassword(fd uintptr) (secret string, err error) {
+	err = fmt.Errorf("not supported")
+	return
+}
+
+// the raw mode is not supported
+func makeRaw(fd uintptr) (restore func(), err error) {
+	err = fmt.Errorf("not supported")
+	return
+}
+
+// the raw mode is not supported
+func readByte(fd uintptr) (b byte, err error) {
+	err = fmt.Errorf("not supported")
+	return
+}
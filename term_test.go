@@ -0,0 +1,37 @@
+package argparse
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestTerminalWidth(t *testing.T) {
+	defer func(columns string, ok bool) {
+		if ok {
+			os.Setenv("COLUMNS", columns)
+		} else {
+			os.Unsetenv("COLUMNS")
+		}
+	}(os.LookupEnv("COLUMNS"))
+	os.Setenv("COLUMNS", "40")
+
+	file, err := ioutil.TempFile("", "argparse")
+	if err != nil {
+		t.Fatalf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if width := TerminalWidth(file); width != 0 {
+		t.Errorf("COLUMNS should be ignored when not a tty: %v", width)
+	}
+
+	defer func(f *os.File, width int) { Stderr, HelpWidth = f, width }(Stderr, HelpWidth)
+	Stderr = file
+	for width, ans := range map[int]int{0: 0, -1: 0, 64: 64, 200: HelpMaxWidth} {
+		if HelpWidth = width; helpWidth() != ans {
+			t.Errorf("help width %v: %v", width, helpWidth())
+		}
+	}
+}
@@ -0,0 +1,107 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package argparse
+
+import (
+	"io"
+	"syscall"
+	"unsafe"
+)
+
+// get the window size of the tty via ioctl, 0 when not a tty
+func ttyWidth(fd uintptr) (width int) {
+	var winsize struct {
+		Row, Col       uint16
+		Xpixel, Ypixel uint16
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&winsize)))
+	if errno == 0 {
+		width = int(winsize.Col)
+	}
+	return
+}
+
+// check the file descriptor is a tty
+func isTerminal(fd uintptr) (ok bool) {
+	var winsize struct {
+		Row, Col       uint16
+		Xpixel, Ypixel uint16
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&winsize)))
+	ok = errno == 0
+	return
+}
+
+// read the line from the tty with echo disabled
+func readPassword(fd uintptr) (secret string, err error) {
+	var origin syscall.Termios
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctl_get_termios, uintptr(unsafe.Pointer(&origin))); errno != 0 {
+		err = errno
+		return
+	}
+
+	termios := origin
+	termios.Lflag &^= syscall.ECHO
+	termios.Lflag |= syscall.ICANON | syscall.ISIG
+	termios.Iflag |= syscall.ICRNL
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctl_set_termios, uintptr(unsafe.Pointer(&termios))); errno != 0 {
+		err = errno
+		return
+	}
+	defer func() {
+		// always restore the terminal
+		syscall.Syscall(syscall.SYS_IOCTL, fd, ioctl_set_termios, uintptr(unsafe.Pointer(&origin)))
+	}()
+
+	buff := []byte{0}
+	for {
+		var n int
+		if n, err = syscall.Read(int(fd), buff); err != nil {
+			return
+		} else if n == 0 || buff[0] == '\n' {
+			break
+		}
+		secret += string(buff)
+	}
+	return
+}
+
+// set the tty as the raw mode to read the key one by one, and return the function to restore
+func makeRaw(fd uintptr) (restore func(), err error) {
+	var origin syscall.Termios
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctl_get_termios, uintptr(unsafe.Pointer(&origin))); errno != 0 {
+		err = errno
+		return
+	}
+
+	termios := origin
+	termios.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG
+	termios.Iflag &^= syscall.ICRNL
+	termios.Cc[syscall.VMIN], termios.Cc[syscall.VTIME] = 1, 0
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctl_set_termios, uintptr(unsafe.Pointer(&termios))); errno != 0 {
+		err = errno
+		return
+	}
+
+	restore = func() {
+		syscall.Syscall(syscall.SYS_IOCTL, fd, ioctl_set_termios, uintptr(unsafe.Pointer(&origin)))
+	}
+	return
+}
+
+// read one byte from the tty
+func readByte(fd uintptr) (b byte, err error) {
+	buff := []byte{0}
+
+	var n int
+	if n, err = syscall.Read(int(fd), buff); err == nil && n == 0 {
+		err = io.EOF
+	}
+	b = buff[0]
+	return
+}
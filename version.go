@@ -0,0 +1,118 @@
+package argparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+var (
+	// the version info injected by the ldflags, like
+	//   -ldflags "-X github.com/cmj0121/argparse.BuildVersion=v1.0.0"
+	BuildVersion string
+	BuildCommit  string
+	BuildTime    string
+)
+
+// the structure provides the version of the program, not named Version which conflicts with the
+// embedded argparse.Version
+type Versioner interface {
+	VersionString() string
+}
+
+// the version info of the program shown by --version
+type VersionInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Dirty     bool   `json:"dirty,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// the version info of the program, from the parser, the ldflags variables and then the
+// build info of the module
+func (parser *ArgParse) VersionInfo() (info VersionInfo) {
+	info = VersionInfo{
+		Name:      parser.Name,
+		Version:   parser.Version,
+		Commit:    BuildCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if fields := strings.Fields(parser.program()); len(fields) > 0 {
+		// the root program name of the sub-command
+		info.Name = fields[0]
+	}
+
+	if info.Version == "" {
+		// use the version from the ldflags
+		info.Version = BuildVersion
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && build.Main.Version != "" {
+			// use the module version, (devel) is shown as devel
+			info.Version = strings.Trim(build.Main.Version, "()")
+		}
+
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.modified":
+				info.Dirty = setting.Value == "true"
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Version == "" {
+		// cannot find the version
+		info.Version = "unknown"
+	}
+	return
+}
+
+// the short form of the version, like "git (v1.0.0)"
+func (info VersionInfo) String() (str string) {
+	str = fmt.Sprintf("%v (%v)", info.Name, info.Version)
+	return
+}
+
+// the verbose form of the version, include the commit, build time and go version
+func (info VersionInfo) Verbose() (str string) {
+	str = info.String()
+
+	if info.Commit != "" {
+		commit := info.Commit
+		if info.Dirty {
+			// the working tree is modified
+			commit = fmt.Sprintf("%v (dirty)", commit)
+		}
+		str = fmt.Sprintf("%v\ncommit:     %v", str, commit)
+	}
+
+	if info.BuildTime != "" {
+		str = fmt.Sprintf("%v\nbuild time: %v", str, info.BuildTime)
+	}
+
+	str = fmt.Sprintf("%v\ngo version: %v", str, info.GoVersion)
+	str = fmt.Sprintf("%v\n%v:   v%d.%d.%d", str, PROJ_NAME, MAJOR, MINOR, MACRO)
+	return
+}
+
+// the JSON form of the version
+func (info VersionInfo) JSON() (str string) {
+	data, _ := json.Marshal(info)
+	str = string(data)
+	return
+}
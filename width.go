@@ -0,0 +1,131 @@
+package argparse
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// zero width joiner, used in the emoji ZWJ sequence
+	RUNE_ZWJ = '\u200d'
+	// the variation selector for the emoji presentation
+	RUNE_VS16 = '\ufe0f'
+)
+
+// the East Asian Wide (W) and Fullwidth (F) ranges, include the emoji presentation
+var wide_table = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F},
+	{0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5},
+	{0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4}, {0x17000, 0x18CFF}, {0x1B000, 0x1B2FF},
+	{0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251},
+	{0x1F300, 0x1F320}, {0x1F32D, 0x1F335}, {0x1F337, 0x1F37C}, {0x1F37E, 0x1F393}, {0x1F3A0, 0x1F3CA},
+	{0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4}, {0x1F3F8, 0x1F43E}, {0x1F440, 0x1F440},
+	{0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D}, {0x1F54B, 0x1F54E}, {0x1F550, 0x1F567}, {0x1F57A, 0x1F57A},
+	{0x1F595, 0x1F596}, {0x1F5A4, 0x1F5A4}, {0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC},
+	{0x1F6D0, 0x1F6D2}, {0x1F6D5, 0x1F6D7}, {0x1F6EB, 0x1F6EC}, {0x1F6F4, 0x1F6FC}, {0x1F7E0, 0x1F7EB},
+	{0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF}, {0x1FA70, 0x1FAFF}, {0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+// calculate the display width of the string on the terminal, which is aware of the East Asian
+// Width, the combining characters and the emoji sequences
+func WidecharSize(widechar string) (siz int) {
+	prev, joined, regional := 0, false, false
+	for _, r := range widechar {
+		switch {
+		case r == RUNE_ZWJ:
+			// the next rune is joined into the previous grapheme
+			joined = true
+			continue
+		case joined:
+			joined = false
+			continue
+		case r == RUNE_VS16:
+			if prev == 1 {
+				// the emoji presentation of the narrow symbol
+				siz, prev = siz+1, 2
+			}
+			continue
+		case r >= 0x1F3FB && r <= 0x1F3FF && prev == 2:
+			// the emoji skin tone modifier
+			continue
+		case r >= 0x1F1E6 && r <= 0x1F1FF:
+			// the regional indicator pair is shown as one flag
+			if regional = !regional; regional {
+				siz, prev = siz+2, 2
+			}
+			continue
+		}
+
+		regional = false
+		if w := RuneWidth(r); w > 0 {
+			siz, prev = siz+w, w
+		}
+	}
+	return
+}
+
+// the display width of the single rune: 0 for the control and combining character, 2 for
+// the wide character and 1 for others
+func RuneWidth(r rune) (width int) {
+	switch {
+	case r == 0 || r < 0x20 || (r >= 0x7F && r < 0xA0):
+		// control character
+		return
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		// combining and format character
+		return
+	case r >= 0xFE00 && r <= 0xFE0F, r >= 0xE0100 && r <= 0xE01EF:
+		// variation selector
+		return
+	case r >= 0x1160 && r <= 0x11FF:
+		// Hangul Jamo medial vowels and final consonants
+		return
+	}
+
+	width = 1
+	if r < wide_table[0][0] {
+		// fast path for the narrow character
+		return
+	}
+
+	low, high := 0, len(wide_table)-1
+	for low <= high {
+		mid := (low + high) / 2
+		switch {
+		case r < wide_table[mid][0]:
+			high = mid - 1
+		case r > wide_table[mid][1]:
+			low = mid + 1
+		default:
+			width = 2
+			return
+		}
+	}
+	return
+}
+
+// pad the string with spaces on the right to the display width
+func padRight(str string, width int) string {
+	if siz := WidecharSize(str); siz < width {
+		// add the space
+		return str + strings.Repeat(" ", width-siz)
+	}
+	return str
+}
+
+// pad the string with spaces on the left to the display width
+func padLeft(str string, width int) string {
+	if siz := WidecharSize(str); siz < width {
+		// add the space
+		return strings.Repeat(" ", width-siz) + str
+	}
+	return str
+}
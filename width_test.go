@@ -0,0 +1,65 @@
+package argparse
+
+import (
+	"testing"
+)
+
+func TestWidecharSize(t *testing.T) {
+	cases := []struct {
+		name  string
+		in    string
+		width int
+	}{
+		{"empty", "", 0},
+		{"ascii", "abc", 3},
+		{"control", "a\tb", 2},
+		{"latin accented", "\u00e9", 1},
+		{"latin word", "café", 4},
+		{"combining acute", "e\u0301", 1},
+		{"combining marks", "a\u0300\u0316", 1},
+		{"CJK", "中文", 4},
+		{"CJK and ascii", "日本語abc", 9},
+		{"hangul", "한국어", 6},
+		{"hangul jamo", "\u1100\u1161\u11a8", 2},
+		{"fullwidth", "ＡＢ", 4},
+		{"ideographic space", "　", 2},
+		{"emoji", "😀", 2},
+		{"emoji skin tone", "\U0001F44D\U0001F3FD", 2},
+		{"emoji ZWJ sequence", "\U0001F468\u200d\U0001F469\u200d\U0001F467", 2},
+		{"emoji flag", "\U0001F1F9\U0001F1FC", 2},
+		{"emoji flags", "\U0001F1F9\U0001F1FC\U0001F1EF\U0001F1F5", 4},
+		{"emoji VS16", "\u2764\ufe0f", 2},
+		{"text symbol", "\u2764", 1},
+		{"mixed", "a中😀é", 6},
+	}
+
+	for _, c := range cases {
+		if width := WidecharSize(c.in); width != c.width {
+			t.Errorf("%v: WidecharSize(%#v) = %d, expect %d", c.name, c.in, width, c.width)
+		}
+	}
+}
+
+func TestPadding(t *testing.T) {
+	cases := []struct {
+		in    string
+		width int
+		left  string
+		right string
+	}{
+		{"ab", 4, "  ab", "ab  "},
+		{"中", 4, "  中", "中  "},
+		{"\u00e9", 3, "  \u00e9", "\u00e9  "},    // precomposed
+		{"e\u0301", 3, "  e\u0301", "e\u0301  "}, // combining acute
+		{"toolong", 3, "toolong", "toolong"},
+	}
+
+	for _, c := range cases {
+		if str := padLeft(c.in, c.width); str != c.left {
+			t.Errorf("padLeft(%#v, %d) = %#v, expect %#v", c.in, c.width, str, c.left)
+		}
+		if str := padRight(c.in, c.width); str != c.right {
+			t.Errorf("padRight(%#v, %d) = %#v, expect %#v", c.in, c.width, str, c.right)
+		}
+	}
+}